@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"starter/internal/app/models"
+	"starter/internal/config"
+	"strings"
+)
+
+func runCreateAdmin(cfg *config.Config, args []string) int {
+	flags := newFlagSet("create-admin")
+	email := flags.String("email", "", "email address of the admin, required")
+	password := flags.String("password", "", "password of the admin, ADMIN_PASSWORD when empty")
+	firstName := flags.String("first-name", "", "first name, the local part of the email when empty")
+	lastName := flags.String("last-name", "Admin", "last name")
+	displayName := flags.String("display-name", "", "display name, the local part of the email when empty")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if *password == "" {
+		// Prefer the environment, flags show up in the process list
+		*password = cfg.Setup.AdminPassword
+	}
+	if *email == "" || *password == "" {
+		fmt.Fprintln(os.Stderr, "create-admin needs -email and a password from -password or ADMIN_PASSWORD")
+		flags.Usage()
+		return 2
+	}
+	// Names are required by the user validation, the email alone is enough to bootstrap an admin
+	localPart, _, _ := strings.Cut(*email, "@")
+	if *firstName == "" {
+		*firstName = localPart
+	}
+	if *displayName == "" {
+		*displayName = localPart
+	}
+
+	app := InitializeApplication(cfg)
+	defer app.db.Close()
+
+	user, err := app.userService.CreateUser(context.Background(), &models.UserRequestDto{
+		UserEmailId:     *email,
+		UserPassword:    *password,
+		UserDisplayName: *displayName,
+		UserFirstName:   *firstName,
+		UserLastName:    *lastName,
+		UserRole:        models.RoleAdmin,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create admin: %s\n", err.Message)
+		return 1
+	}
+	fmt.Printf("Created admin %s with id %d\n", user.UserEmailId, user.UserId)
+	return 0
+}
+
+// runReencryptUsers encrypts the users written before field encryption and moves every user to
+// the active AES key, to run after the migrations and after each key rotation.
+func runReencryptUsers(cfg *config.Config, args []string) int {
+	flags := newFlagSet("reencrypt-users")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	app := InitializeApplication(cfg)
+	defer app.db.Close()
+
+	rewritten, err := app.userRepository.Reencrypt(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed after re-encrypting %d user(s): %s\n", rewritten, err.Message)
+		return 1
+	}
+	fmt.Printf("Re-encrypted %d user(s)\n", rewritten)
+	return 0
+}
@@ -2,33 +2,46 @@ package main
 
 import (
 	"starter/internal/app/controllers"
+	"starter/internal/app/health"
 	Repository "starter/internal/app/repository"
 	"starter/internal/app/services"
 	"starter/internal/config"
 )
 
 type Application struct {
+	cfg            *config.Config
 	db             config.DBPool
 	crudRepo       Repository.CRUDRepository
 	restCaller     services.RestCaller
 	routes         Router
 	userController controllers.UserController
 	userRepository Repository.UserRepository
+	userService    services.UserService
+	authService    services.AuthService
+	readiness      health.Readiness
 }
 
 func NewApplication(
+	cfg *config.Config,
 	db config.DBPool,
 	crudRepo Repository.CRUDRepository,
 	userRepository Repository.UserRepository,
 	restCaller services.RestCaller,
 	routes Router,
-	userController controllers.UserController) *Application {
+	userController controllers.UserController,
+	userService services.UserService,
+	authService services.AuthService,
+	readiness health.Readiness) *Application {
 	return &Application{
+		cfg:            cfg,
 		db:             db,
 		crudRepo:       crudRepo,
 		restCaller:     restCaller,
 		routes:         routes,
 		userController: userController,
 		userRepository: userRepository,
+		userService:    userService,
+		authService:    authService,
+		readiness:      readiness,
 	}
 }
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"starter/internal/app/tracing"
+	"starter/internal/config"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// command is one mode of the binary, selected by the first argument.
+type command struct {
+	name     string
+	synopsis string
+	summary  string
+	// standalone commands run without loading the configuration, cfg is then nil
+	standalone bool
+	// logFile is set for the long-running commands, the others log to the console only so that they
+	// never rotate the log file of a running server
+	logFile bool
+	run     func(cfg *config.Config, args []string) int
+}
+
+func commandList() []command {
+	return []command{
+		{name: "server", synopsis: "[flags]", summary: "Start the HTTP API (default)", logFile: true, run: runServer},
+		{name: "worker", synopsis: "[flags]", summary: "Run background jobs such as purging expired sessions",
+			logFile: true, run: runWorker},
+		{name: "migrate", synopsis: "up | down [steps] | status", summary: "Apply, revert or list database migrations", run: runMigrate},
+		{name: "seed", synopsis: "[flags]", summary: "Load development data from a SQL file", run: runSeed},
+		{name: "create-admin", synopsis: "-email <email> [flags]", summary: "Create an admin user", run: runCreateAdmin},
+		{name: "reencrypt-users", synopsis: "", summary: "Encrypt users with the active AES key and fill email indexes", run: runReencryptUsers},
+		{name: "healthcheck", synopsis: "[flags]", summary: "Probe the health endpoint of a running server", run: runHealthcheck},
+		{name: "secrets", synopsis: "generate-key | encrypt -in <json> | decrypt -in <file> [flags]",
+			summary: "Generate keys and manage the encrypted secrets file", standalone: true, run: runSecrets},
+		{name: "version", synopsis: "[flags]", summary: "Print build information", standalone: true, run: runVersion},
+	}
+}
+
+// dispatch runs the command named by the first argument and returns the process exit code.
+// Without a command, or when the first argument is a flag, the server is started.
+func dispatch(args []string) int {
+	name := "server"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	} else if len(args) > 0 && (args[0] == "-h" || args[0] == "-help" || args[0] == "--help") {
+		name = "help"
+	}
+	if name == "help" {
+		printUsage(os.Stdout)
+		return 0
+	}
+	for _, cmd := range commandList() {
+		if cmd.name == name && cmd.standalone {
+			return cmd.run(nil, args)
+		}
+		if cmd.name == name {
+			cfg, err := config.Load()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+				return 2
+			}
+			logConfig := cfg.Log
+			if !cmd.logFile {
+				logConfig.Sinks = strings.Join(logConfig.ConsoleSinks(), ",")
+			}
+			if err := setupLogging(logConfig); err != nil {
+				fmt.Fprintf(os.Stderr, "cannot open the log sinks: %v\n", err)
+				return 1
+			}
+			defer closeLogFile()
+			shutdownTracing, err := tracing.Setup(cfg.Tracing)
+			if err != nil {
+				logrus.Errorf("Tracing disabled: %v", err)
+			} else {
+				defer flushTraces(shutdownTracing)
+			}
+			return cmd.run(cfg, args)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	printUsage(os.Stderr)
+	return 2
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: main <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commandList() {
+		fmt.Fprintf(w, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "main <command> -h" for the flags of a command.`)
+}
+
+// newFlagSet returns a flag set whose help text shows the synopsis and summary of the named command.
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		for _, cmd := range commandList() {
+			if cmd.name == name {
+				fmt.Fprintf(flags.Output(), "usage: %s\n\n%s\n", strings.TrimSpace("main "+cmd.name+" "+cmd.synopsis), cmd.summary)
+			}
+		}
+		var hasFlags bool
+		flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintln(flags.Output(), "\nFlags:")
+			flags.PrintDefaults()
+		}
+	}
+	return flags
+}
+
+// parseFlags parses args and reports whether the command should go on. When it should not,
+// code is the exit code: 0 after -h, 2 after invalid flags.
+func parseFlags(flags *flag.FlagSet, args []string) (code int, ok bool) {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0, false
+		}
+		return 2, false
+	}
+	return 0, true
+}
+
+// flushTraces exports the spans still buffered, giving up after a few seconds so that an
+// unreachable collector cannot hold the exit.
+func flushTraces(shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		logrus.Warnf("Failed to flush traces: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"starter/internal/app/buildinfo"
+	"starter/internal/config"
+	"time"
+)
+
+// runHealthcheck probes a running server, it is meant for container health checks
+// where no HTTP client is installed.
+func runHealthcheck(cfg *config.Config, args []string) int {
+	flags := newFlagSet("healthcheck")
+	url := flags.String("url", "http://127.0.0.1:"+cfg.Server.Port+"/internal/health/live", "health endpoint to probe")
+	timeout := flags.Duration("timeout", 3*time.Second, "request timeout")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	client := &http.Client{Timeout: *timeout}
+	resp, err := client.Get(*url)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unhealthy: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		fmt.Fprintf(os.Stderr, "unhealthy: %s returned %d\n", *url, resp.StatusCode)
+		return 1
+	}
+	fmt.Println("healthy")
+	return 0
+}
+
+func runVersion(_ *config.Config, args []string) int {
+	flags := newFlagSet("version")
+	asJSON := flags.Bool("json", false, "print as JSON")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	info := buildinfo.Get()
+	if *asJSON {
+		if err := json.NewEncoder(os.Stdout).Encode(info); err != nil {
+			return 1
+		}
+		return 0
+	}
+	fmt.Println(info)
+	return 0
+}
@@ -2,38 +2,44 @@ package main
 
 import (
 	"io"
-	"net/http"
 	"os"
 	_ "starter/docs"
-	"starter/internal/app/utils"
+	"starter/internal/app/logging"
+	"starter/internal/config"
 
-	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 )
 
-var serverPort = "4000"
+// logSinks are the log destinations opened by setupLogging.
+var logSinks *logging.Sinks
 
-func Init(app *Application, appType string) *Application {
-	// Load environment variables from .env file
-	err := godotenv.Load()
+// setupLogging configures logrus from the validated configuration, it runs before any command.
+func setupLogging(logConfig config.LogConfig) error {
+	sinks, err := logging.OpenSinks(logConfig)
 	if err != nil {
-		logrus.Warn("No .env file found")
+		return err
 	}
-	//logrus configuration
-	loglevel := utils.GetEnvAsString("APPLICATION_LOG_LEVEL", "info")
-	level, _ := logrus.ParseLevel(loglevel)
-	logrus.SetFormatter(&logrus.JSONFormatter{})
-	logrus.SetLevel(level)
-	// Set up logging to both file and console
-	logFile, err := os.OpenFile("application.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND,
-		0666)
-	if err != nil {
-		logrus.Info("Failed to log to file, using default stderr")
-	} else {
-		mw := io.MultiWriter(os.Stdout, logFile)
-		logrus.SetOutput(mw)
+	logSinks = sinks
+	level, _ := logrus.ParseLevel(logConfig.Level)
+	// The sinks write the entries themselves, each in its own format
+	logrus.SetOutput(io.Discard)
+	// Hooks fire before the sinks format an entry, none of them sees the unredacted values
+	logrus.AddHook(logging.NewRedactionHook(logConfig.RedactParamList()))
+	// Levels are changed at runtime through the level control, never on the logger directly
+	logging.DefaultLevels().Install(sinks, level)
+	return nil
+}
+
+// closeLogFile flushes and closes the log files, later entries only go to stdout.
+func closeLogFile() {
+	if logSinks == nil {
+		return
+	}
+	logging.DefaultLevels().SetFormatter(logSinks.Console())
+	if err := logSinks.Close(); err != nil {
+		logrus.Warnf("Failed to close the log file: %v", err)
 	}
-	return app
+	logSinks = nil
 }
 
 // @title           Golang Starter Application
@@ -45,18 +51,11 @@ func Init(app *Application, appType string) *Application {
 // @host      localhost:4000
 // @BasePath  /
 // @externalDocs.description  OpenAPI
-func main() {
-	appType := os.Args[1]
-	app := Init(InitializeApplication(), appType)
-
-	defer app.db.Close()
 
-	logrus.Info("Loading gin server")
-	//Setup routes and start service
-	r := app.routes.SetupRouter()
-	server := &http.Server{
-		Addr:    ":" + serverPort,
-		Handler: r,
-	}
-	logrus.Fatal(server.ListenAndServe())
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token from /auth/login, sent as "Bearer <token>"
+func main() {
+	os.Exit(dispatch(os.Args[1:]))
 }
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"starter/internal/app/migrations"
+	"starter/internal/config"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runMigrate handles `main migrate up|down [steps]|status`.
+func runMigrate(cfg *config.Config, args []string) int {
+	flags := newFlagSet("migrate")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	args = flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		return 2
+	}
+	db := config.ConnectDB(cfg.Database)
+	defer db.Close()
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		logrus.Errorf("Failed to load migrations: %v", err)
+		return 1
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			logrus.Errorf("Migration failed after applying %d migration(s): %v", applied, err)
+			return 1
+		}
+		logrus.Infof("Applied %d migration(s)", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				flags.Usage()
+				return 2
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			logrus.Errorf("Revert failed after reverting %d migration(s): %v", reverted, err)
+			return 1
+		}
+		logrus.Infof("Reverted %d migration(s)", reverted)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			logrus.Errorf("Failed to read migration status: %v", err)
+			return 1
+		}
+		printStatus(statuses)
+	default:
+		flags.Usage()
+		return 2
+	}
+	return 0
+}
+
+// runSeed executes the seed script in a single transaction.
+func runSeed(cfg *config.Config, args []string) int {
+	flags := newFlagSet("seed")
+	seedFile := flags.String("file", cfg.Setup.SeedFile, "SQL file to run, SEED_FILE")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	script, err := os.ReadFile(*seedFile)
+	if err != nil {
+		logrus.Errorf("Failed to read seed file %s: %v", *seedFile, err)
+		return 1
+	}
+	db := config.ConnectDB(cfg.Database)
+	defer db.Close()
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		logrus.Errorf("Failed to load migrations: %v", err)
+		return 1
+	}
+	if err := migrator.Seed(context.Background(), string(script)); err != nil {
+		logrus.Errorf("Failed to seed the database: %v", err)
+		return 1
+	}
+	logrus.Infof("Seeded the database from %s", *seedFile)
+	return 0
+}
+
+func printStatus(statuses []migrations.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	w.Flush()
+}
@@ -4,12 +4,13 @@ import (
 	"net/http"
 	"starter/internal/app/controllers"
 	"starter/internal/app/middlewares"
-	"starter/internal/app/utils"
+	"starter/internal/app/ratelimit"
+	"starter/internal/app/services"
 	"starter/internal/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/time/rate"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 //go:generate mockery --name Router
@@ -19,46 +20,68 @@ type Router interface {
 type router struct {
 	db                 config.DBPool
 	userController     controllers.UserController
+	authController     controllers.AuthController
 	internalController controllers.InternalController
+	authService        services.AuthService
+	rateLimitStore     ratelimit.Store
+	cfg                *config.Config
 }
 
-func NewRouter(db config.DBPool, internalController controllers.InternalController, userController controllers.UserController) Router {
+func NewRouter(db config.DBPool, internalController controllers.InternalController, userController controllers.UserController,
+	authController controllers.AuthController, authService services.AuthService, rateLimitStore ratelimit.Store, cfg *config.Config) Router {
 
 	return &router{
 		db:                 db,
+		cfg:                cfg,
 		userController:     userController,
+		authController:     authController,
 		internalController: internalController,
+		authService:        authService,
+		rateLimitStore:     rateLimitStore,
 	}
 }
 
 func (r *router) SetupRouter() *gin.Engine {
-	gin.SetMode(utils.GetEnvAsString("GIN_MODE", gin.DebugMode))
+	gin.SetMode(r.cfg.Server.GinMode)
 	ginRouter := gin.New()
 	ginRouter.HandleMethodNotAllowed = true
+	// Without trusted proxies ClientIP is the peer address, X-Forwarded-For could otherwise be
+	// forged to get a fresh rate limit bucket on every request
+	if err := ginRouter.SetTrustedProxies(r.cfg.Server.TrustedProxyList()); err != nil {
+		logrus.Errorf("Ignoring TRUSTED_PROXIES: %v", err)
+	}
 	//Setting up middlewares
 	// Global middlewares
+	// One span per request, probes excepted as they would drown the traces
+	ginRouter.Use(otelgin.Middleware(r.cfg.Tracing.ServiceName, otelgin.WithFilter(func(req *http.Request) bool {
+		return req.URL.Path != "/internal/health/live" && req.URL.Path != "/internal/health/ready"
+	})))
 	ginRouter.Use(middlewares.RequestIDMiddleware())
+	ginRouter.Use(middlewares.MetricsMiddleware())
 
-	//logrus configuration
-	loglevel := utils.GetEnvAsString("APPLICATION_LOG_LEVEL", "info")
-	level, _ := logrus.ParseLevel(loglevel)
-	log := logrus.New()
-	log.SetFormatter(&logrus.JSONFormatter{})
-	log.SetLevel(level)
-	logrus.Infof("Setting level:%v", level)
-	ginRouter.Use(middlewares.LoggerMiddleware(log))
+	// Requests log through the standard logger configured by setupLogging, like every other component,
+	// so that the level set at /internal/log applies to them too
+	ginRouter.Use(middlewares.LoggerMiddleware(logrus.StandardLogger()))
 
 	ginRouter.Use(gin.Recovery())
 
-	// Get the rate limit, with a default value
-	reqPerSec := utils.GetEnvAsInt("RATE_LIMIT_PER_SEC", 100)
-	// Set up rate limiter
-	limiter := rate.NewLimiter(rate.Limit(reqPerSec), reqPerSec)
+	// One rate limiter per route group, all counting per client in the same store
+	rateLimit := r.cfg.RateLimit
+	newLimiter := func(name string, spec string) *middlewares.RateLimiter {
+		// The specs were checked by config.Validate
+		rule, _ := rateLimit.Rule(spec)
+		return middlewares.NewRateLimiter(name, rule, r.rateLimitStore)
+	}
 
+	authMiddleware := middlewares.AuthMiddleware(r.authService)
 	//Setup Internal Route
-	controllers.SetupInternalRoute(ginRouter, r.internalController, limiter)
+	controllers.SetupInternalRoute(ginRouter, r.internalController, newLimiter("internal", rateLimit.Internal),
+		newLimiter("health", rateLimit.Health), authMiddleware)
 	//Setup User controller router
-	controllers.SetupUserRoute(ginRouter, r.userController, limiter)
+	controllers.SetupUserRoute(ginRouter, r.userController, newLimiter("user-ip", rateLimit.UserIP), newLimiter("user", rateLimit.User),
+		authMiddleware, r.cfg.Server.RequestTimeout)
+	//Setup Auth controller router
+	controllers.SetupAuthRoute(ginRouter, r.authController, newLimiter("auth", rateLimit.Auth), r.cfg.Server.RequestTimeout)
 	return ginRouter
 }
 func testResponse(c *gin.Context) {
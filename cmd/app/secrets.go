@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"starter/internal/config"
+	"strings"
+)
+
+// runSecrets handles `main secrets generate-key|encrypt|decrypt`, the tooling around the
+// encrypted secrets file. The master key is read from SECRETS_MASTER_KEY, never from a flag,
+// so that it does not end up in the shell history.
+func runSecrets(_ *config.Config, args []string) int {
+	flags := newFlagSet("secrets")
+	var action string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+	in := flags.String("in", "", "file to read, a JSON object for encrypt or a sealed file for decrypt")
+	out := flags.String("out", "", "file to write instead of stdout")
+	size := flags.Int("bytes", 32, "length of the key generated by generate-key: 16, 24 or 32")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	var output []byte
+	switch action {
+	case "generate-key":
+		if *size != 16 && *size != 24 && *size != 32 {
+			flags.Usage()
+			return 2
+		}
+		key, err := config.GenerateKey(*size)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to generate a key: %v\n", err)
+			return 1
+		}
+		output = []byte(key + "\n")
+	case "encrypt":
+		content, err := os.ReadFile(*in)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "-in: %v\n", err)
+			return 1
+		}
+		var secrets map[string]string
+		if err := json.Unmarshal(content, &secrets); err != nil {
+			fmt.Fprintf(os.Stderr, "%s must hold a JSON object of strings: %v\n", *in, err)
+			return 1
+		}
+		output, err = config.EncryptSecrets(secrets, os.Getenv("SECRETS_MASTER_KEY"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	case "decrypt":
+		content, err := os.ReadFile(*in)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "-in: %v\n", err)
+			return 1
+		}
+		secrets, err := config.DecryptSecrets(content, os.Getenv("SECRETS_MASTER_KEY"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		output, _ = json.MarshalIndent(secrets, "", "  ")
+		output = append(output, '\n')
+	default:
+		flags.Usage()
+		return 2
+	}
+
+	if *out == "" {
+		os.Stdout.Write(output)
+		return 0
+	}
+	if err := os.WriteFile(*out, output, 0600); err != nil {
+		fmt.Fprintf(os.Stderr, "-out: %v\n", err)
+		return 1
+	}
+	return 0
+}
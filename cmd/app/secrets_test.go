@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSecrets_GeneratedKeyRoundTrips(t *testing.T) {
+	dir := t.TempDir()
+	keyFile, plainFile := filepath.Join(dir, "master.key"), filepath.Join(dir, "secrets.json")
+	sealedFile, openedFile := filepath.Join(dir, "secrets.enc"), filepath.Join(dir, "opened.json")
+	require.NoError(t, os.WriteFile(plainFile, []byte(`{"JWT_SECRET":"sealed-secret"}`), 0600))
+
+	require.Equal(t, 0, runSecrets(nil, []string{"generate-key", "-out", keyFile}))
+	key, err := os.ReadFile(keyFile)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(key), "base64:"))
+	t.Setenv("SECRETS_MASTER_KEY", strings.TrimSpace(string(key)))
+
+	require.Equal(t, 0, runSecrets(nil, []string{"encrypt", "-in", plainFile, "-out", sealedFile}))
+	require.Equal(t, 0, runSecrets(nil, []string{"decrypt", "-in", sealedFile, "-out", openedFile}))
+	opened, err := os.ReadFile(openedFile)
+	require.NoError(t, err)
+	var secrets map[string]string
+	require.NoError(t, json.Unmarshal(opened, &secrets))
+	assert.Equal(t, map[string]string{"JWT_SECRET": "sealed-secret"}, secrets)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"starter/internal/app/buildinfo"
+	"starter/internal/app/metrics"
+	"starter/internal/config"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func runServer(cfg *config.Config, args []string) int {
+	flags := newFlagSet("server")
+	flags.StringVar(&cfg.Server.Port, "port", cfg.Server.Port, "port to listen on, SERVER_PORT")
+	flags.DurationVar(&cfg.Server.ShutdownGracePeriod, "grace-period", cfg.Server.ShutdownGracePeriod,
+		"time given to in-flight requests to finish on shutdown, SHUTDOWN_GRACE_PERIOD")
+	flags.DurationVar(&cfg.Server.ShutdownDrainDelay, "drain-delay", cfg.Server.ShutdownDrainDelay,
+		"time between failing the health check and closing the listener, SHUTDOWN_DRAIN_DELAY")
+	flags.BoolVar(&cfg.Server.RunWorker, "worker", cfg.Server.RunWorker,
+		"also run the background jobs in this process, SERVER_RUN_WORKER")
+	flags.DurationVar(&cfg.Worker.Interval, "worker-interval", cfg.Worker.Interval,
+		"time between two runs of the background jobs, WORKER_INTERVAL")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	// Flags override the loaded values, check them again
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid flags:\n%v\n", err)
+		return 2
+	}
+
+	app := InitializeApplication(cfg)
+	defer app.db.Close()
+	if err := metrics.RegisterPool(app.db); err != nil {
+		logrus.Warnf("Connection pool metrics disabled: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Background jobs get their own context so they stop only after the server has drained
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	if cfg.Server.RunWorker {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			runJobLoop(workerCtx, app, cfg.Worker.Interval)
+		}()
+	}
+
+	logrus.Infof("Loading gin server %s", buildinfo.Get())
+	//Setup routes and start service
+	r := app.routes.SetupRouter()
+	server := &http.Server{
+		Addr:    ":" + cfg.Server.Port,
+		Handler: r,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	code := 0
+	select {
+	case err := <-serverErr:
+		logrus.Errorf("Server stopped: %v", err)
+		code = 1
+	case <-ctx.Done():
+		// A second signal falls back to the default behaviour and kills the process
+		stop()
+		code = shutdown(app, server, serverErr, cfg.Server.ShutdownDrainDelay, cfg.Server.ShutdownGracePeriod)
+	}
+
+	stopWorkers()
+	workers.Wait()
+	logrus.Info("Server exited")
+	return code
+}
+
+// shutdown fails the health check, waits drainDelay for load balancers to notice, then stops
+// accepting connections and lets in-flight requests finish within gracePeriod.
+func shutdown(app *Application, server *http.Server, serverErr <-chan error, drainDelay, gracePeriod time.Duration) int {
+	logrus.Info("Shutdown requested, marking the service as not ready")
+	app.readiness.SetReady(false)
+	time.Sleep(drainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancel()
+	logrus.Infof("Draining in-flight requests, waiting at most %s", gracePeriod)
+	if err := server.Shutdown(ctx); err != nil {
+		logrus.Errorf("Requests still running after the grace period, closing them: %v", err)
+		server.Close()
+		return 1
+	}
+	if err := <-serverErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logrus.Errorf("Server stopped: %v", err)
+		return 1
+	}
+	return 0
+}
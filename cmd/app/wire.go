@@ -5,6 +5,9 @@ package main
 
 import (
 	"starter/internal/app/controllers"
+	"starter/internal/app/encryption"
+	"starter/internal/app/health"
+	"starter/internal/app/ratelimit"
 	Repository "starter/internal/app/repository"
 	"starter/internal/app/services"
 	"starter/internal/config"
@@ -12,14 +15,22 @@ import (
 	"github.com/google/wire"
 )
 
-func InitializeApplication() *Application {
-	wire.Build(config.ConnectDB,
+func InitializeApplication(cfg *config.Config) *Application {
+	wire.Build(wire.FieldsOf(new(*config.Config), "Database", "Auth", "Security", "Health", "Log", "RateLimit"),
+		config.ConnectDB,
+		encryption.NewFieldCipher,
 		Repository.NewUserRepository,
+		Repository.NewSessionRepository,
 		services.NewUserService,
+		services.NewAuthService,
 		controllers.NewUserController,
+		controllers.NewAuthController,
 		controllers.NewInternalController,
 		Repository.NewCRUDRepository,
 		services.NewDefaultRestCaller,
+		health.NewReadiness,
+		health.NewDefaultRegistry,
+		ratelimit.NewStore,
 		NewRouter,
 		NewApplication,
 	)
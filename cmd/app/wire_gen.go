@@ -8,6 +8,9 @@ package main
 
 import (
 	"starter/internal/app/controllers"
+	"starter/internal/app/encryption"
+	"starter/internal/app/health"
+	"starter/internal/app/ratelimit"
 	"starter/internal/app/repository"
 	"starter/internal/app/services"
 	"starter/internal/config"
@@ -19,15 +22,28 @@ import (
 
 // Injectors from wire.go:
 
-func InitializeApplication() *Application {
-	dbPool := config.ConnectDB()
-	crudRepository := Repository.NewCRUDRepository(dbPool)
-	userRepository := Repository.NewUserRepository(crudRepository)
+func InitializeApplication(cfg *config.Config) *Application {
+	databaseConfig := cfg.Database
+	dbPool := config.ConnectDB(databaseConfig)
+	crudRepository := Repository.NewCRUDRepository(dbPool, databaseConfig)
+	securityConfig := cfg.Security
+	fieldCipher := encryption.NewFieldCipher(securityConfig)
+	userRepository := Repository.NewUserRepository(crudRepository, fieldCipher)
 	restCaller := services.NewDefaultRestCaller()
-	userService := services.NewUserService(userRepository)
-	internalController := controllers.NewInternalController(dbPool, userService)
+	sessionRepository := Repository.NewSessionRepository(crudRepository)
+	userService := services.NewUserService(crudRepository, userRepository, sessionRepository)
+	readiness := health.NewReadiness()
+	healthConfig := cfg.Health
+	logConfig := cfg.Log
+	registry := health.NewDefaultRegistry(dbPool, restCaller, healthConfig, logConfig)
+	internalController := controllers.NewInternalController(dbPool, userService, readiness, registry, cfg)
 	userController := controllers.NewUserController(userService)
-	mainRouter := NewRouter(dbPool, internalController, userController)
-	application := NewApplication(dbPool, crudRepository, userRepository, restCaller, mainRouter, userController)
+	authConfig := cfg.Auth
+	authService := services.NewAuthService(userService, userRepository, sessionRepository, authConfig)
+	authController := controllers.NewAuthController(authService)
+	rateLimitConfig := cfg.RateLimit
+	store := ratelimit.NewStore(dbPool, rateLimitConfig)
+	mainRouter := NewRouter(dbPool, internalController, userController, authController, authService, store, cfg)
+	application := NewApplication(cfg, dbPool, crudRepository, userRepository, restCaller, mainRouter, userController, userService, authService, readiness)
 	return application
 }
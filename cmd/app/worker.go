@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"starter/internal/config"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func runWorker(cfg *config.Config, args []string) int {
+	flags := newFlagSet("worker")
+	flags.DurationVar(&cfg.Worker.Interval, "interval", cfg.Worker.Interval, "time between two runs of the jobs, WORKER_INTERVAL")
+	once := flags.Bool("once", false, "run the jobs once and exit")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if cfg.Worker.Interval <= 0 {
+		logrus.Errorf("Worker interval must be positive, got %s", cfg.Worker.Interval)
+		return 2
+	}
+
+	app := InitializeApplication(cfg)
+	defer app.db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *once {
+		runJobs(ctx, app)
+		return 0
+	}
+	runJobLoop(ctx, app, cfg.Worker.Interval)
+	return 0
+}
+
+// runJobLoop runs the jobs right away and then every interval, until ctx is cancelled.
+// A run in progress finishes with a cancelled context before the loop returns.
+func runJobLoop(ctx context.Context, app *Application, interval time.Duration) {
+	logrus.Infof("Worker started, running jobs every %s", interval)
+	runJobs(ctx, app)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("Worker stopped")
+			return
+		case <-ticker.C:
+			runJobs(ctx, app)
+		}
+	}
+}
+
+// runJobs runs every background job once. A failing job is logged and retried on the next run.
+func runJobs(ctx context.Context, app *Application) {
+	if err := app.authService.PurgeExpiredSessions(ctx); err != nil {
+		logrus.Errorf("Failed to purge expired sessions: %s", err.Message)
+		return
+	}
+	logrus.Debug("Purged expired sessions")
+}
@@ -3,12 +3,13 @@ package constants
 var WRONG_CREDENTIALS = "Please check if username and password is provided correctly"
 var TOKEN_GENERATE_FAIL = "Failed to generate JWT token"
 var INVALID_TOKEN = "Authorization token is not valid and SessionId cannot be extracted"
-var USER_SESSION_FAILED_CREATE = "Failed to create session for User:"
+var USER_SESSION_FAILED_CREATE = "Failed to create session for the user"
 var MISSING_AUTH_TOKEN = "Authorization Token is missing in the request"
 var PARSE_TOKEN_FAIL = "Failed to parse authentication token"
 var INVALID_SESSION = "Provided user session is invalid"
 var REQUEST_ID_GENERATION_FAILED = "Failed to generate request id"
 var TOKEN_SIGNING_FAILED = "Failed to sign the token"
+var FAILED_TO_PURGE_SESSIONS = "Failed to purge expired sessions"
 
 const WARNING = "warning"
 const UNDEFINED = "undefined"
@@ -30,11 +31,33 @@ var FAILED_TOTAL_ROWS = "Failed to count total rows"
 var FAILED_SCAN = "Failed to scan results"
 var ERRO_PROCESSING_FAIL = "Error processing %s"
 var NO_ROWS_AFFECTED = "No rows affected for %s"
+var DUPLICATE_OBJ = "%s already exists"
+var QUERY_TIMEOUT = "Timed out while querying %s"
+var QUERY_CANCELLED = "Request was cancelled while querying %s"
+var FAILED_TO_ENCRYPT = "Failed to encrypt %s"
+var FAILED_TO_DECRYPT = "Failed to decrypt %s"
 
 var POST_READ_ERROR = "Not able to read POST Body"
 var INVALID_ID = "Invalid ID"
 var INVALID_EMAILID = "Provided Email address is invalid"
 var INVALID_PASSWORD = "Invalid password"
+var INVALID_ROLE = "Provided user role is invalid"
+var USER_ALREADY_EXISTS = "User with the provided email already exists"
+var PASSWORD_HASH_FAILED = "Failed to secure the provided password"
+var FAILED_TO_CREATE_USER = "Failed to create user, please try again."
+var FAILED_TO_FETCH_USER = "Failed to fetch user details, please try again."
+var FAILED_TO_UPDATE_USER = "Failed to update user, please try again."
+var FAILED_TO_DELETE_USER = "Failed to delete user, please try again."
+var OLD_PASSWORD_MISMATCH = "Provided old password does not match"
+var PASSWORD_NOT_CHANGED = "New password must be different from the old password"
+var CANNOT_DELETE_SELF = "Users cannot delete their own account"
 
+var INVALID_FILTER = "Invalid filter %s provided"
+var EMAIL_FILTER_NOT_ADDRESS = "The email filter takes a full email address, emails are stored encrypted and cannot be searched by prefix"
+var ENCRYPTED_SORT_FIELD = "Users cannot be sorted by %s, emails and names are stored encrypted"
 var EMPTY_FIELD = "Invalid Field %s provided, please check the content is not empty"
 var UNAUTHORIZED = "Unauthorized to make this request"
+var TOO_MANY_REQUESTS = "Too many requests"
+var SERVICE_SHUTTING_DOWN = "Service is shutting down"
+var DEPENDENCIES_UNAVAILABLE = "Critical dependencies unavailable: %s"
+var FORBIDDEN_ROLE = "User role is not allowed to make this request"
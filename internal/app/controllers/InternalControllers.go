@@ -1,89 +1,258 @@
 package controllers
 
 import (
-	"context"
+	"fmt"
 	"net/http"
 	_ "starter/docs"
+	"starter/internal/app/constants"
+	"starter/internal/app/health"
+	"starter/internal/app/logging"
 	"starter/internal/app/middlewares"
+	"starter/internal/app/models"
 	"starter/internal/app/services"
 	"starter/internal/app/utils"
 	"starter/internal/config"
+	"strings"
+	"time"
 
 	"github.com/gin-contrib/pprof"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
-	"golang.org/x/time/rate"
 )
 
 //go:generate mockery --name InternalController
 type InternalController interface {
+	GetLogLevel(c *gin.Context)
 	SetLogLevel(c *gin.Context)
-	HealthCheck(c *gin.Context)
+	ResetLogLevel(c *gin.Context)
+	Live(c *gin.Context)
+	Ready(c *gin.Context)
+	HealthDetails(c *gin.Context)
+	GetConfig(c *gin.Context)
 }
 
 type internal struct {
-	db          config.DBPool
-	userService services.UserService
+	db             config.DBPool
+	logLevels      *logging.LevelControl
+	userService    services.UserService
+	readiness      health.Readiness
+	healthRegistry health.Registry
+	cfg            *config.Config
 }
 
-func NewInternalController(db config.DBPool, userService services.UserService) InternalController {
-	return &internal{db: db, userService: userService}
+func NewInternalController(db config.DBPool, userService services.UserService, readiness health.Readiness,
+	healthRegistry health.Registry, cfg *config.Config) InternalController {
+	return &internal{db: db, userService: userService, readiness: readiness, healthRegistry: healthRegistry, cfg: cfg,
+		logLevels: logging.DefaultLevels()}
+}
+
+// Temporary levels last from minLogLevelTTL to maxLogLevelTTL, longer investigations can set a
+// level without a TTL
+const (
+	minLogLevelTTL = time.Second
+	maxLogLevelTTL = 24 * time.Hour
+)
+
+// GetLogLevel Reports the log levels in effect
+// @Summary Get Log Level
+// @Description Returns the global log level and the component overrides, with their expiry when set with a TTL
+// @Produce json
+// @Tags Internal
+// @Security BearerAuth
+// @Success 200 {object} logging.LevelState
+// @Failure 401 {object} utils.ErrorMessage
+// @Failure 403 {object} utils.ErrorMessage
+// @Router /internal/log [get]
+func (i *internal) GetLogLevel(c *gin.Context) {
+	utils.RespondJSON(c, http.StatusOK, i.logLevels.State())
 }
 
 // SetLogLevel Sets Logrus Log level
 // @Summary Set Log Level
-// @Description Set Log Level
+// @Description Sets the global log level, or the level of one component such as repository, services or http.
+// @Description With a ttl the previous level is restored once it elapses.
 // @Produce json
 // @Tags Internal
-// @Param level path string true "Log Level"
-// @Success 200 {object} map[string]interface{}
+// @Security BearerAuth
+// @Param level path string true "Log Level" Enums(panic, fatal, error, warn, info, debug, trace)
+// @Param component query string false "Component, all components when omitted"
+// @Param ttl query string false "Time after which the previous level is restored, e.g. 10m, from 1s to 24h"
+// @Success 200 {object} logging.LevelState
 // @Failure 400 {object} utils.ErrorMessage
-// @Router /log/{level} [put]
+// @Failure 401 {object} utils.ErrorMessage
+// @Failure 403 {object} utils.ErrorMessage
+// @Router /internal/log/{level} [put]
 func (i *internal) SetLogLevel(c *gin.Context) {
-	level := c.Param("level")
-	switch level {
-	case "debug":
-		logrus.SetLevel(logrus.DebugLevel)
-	case "info":
-		logrus.SetLevel(logrus.InfoLevel)
-	default:
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid log level")
+	level, err := logging.ParseLevel(c.Param("level"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	var ttl time.Duration
+	if raw := c.Query("ttl"); raw != "" {
+		ttl, err = time.ParseDuration(raw)
+		if err != nil || ttl < minLogLevelTTL || ttl > maxLogLevelTTL {
+			utils.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("ttl must be a duration between %s and %s", minLogLevelTTL, maxLogLevelTTL))
+			return
+		}
+	}
+	component := c.Query("component")
+	if !validComponent(c, component) {
 		return
 	}
-	utils.RespondJSON(c, http.StatusOK, gin.H{"message": "Log level set to " + level})
+	state := i.logLevels.SetLevel(component, level, ttl)
+	message := fmt.Sprintf("Log level of %s set to %s", orGlobal(component), level)
+	if ttl > 0 {
+		message += " for " + ttl.String()
+	}
+	logging.FromContext(c.Request.Context()).Warn(message)
+	utils.RespondJSON(c, http.StatusOK, state)
+}
+
+// ResetLogLevel Removes a runtime log level
+// @Summary Reset Log Level
+// @Description Removes the override of a component, or restores the configured global level
+// @Produce json
+// @Tags Internal
+// @Security BearerAuth
+// @Param component query string false "Component, the global level when omitted"
+// @Success 200 {object} logging.LevelState
+// @Failure 400 {object} utils.ErrorMessage
+// @Failure 401 {object} utils.ErrorMessage
+// @Failure 403 {object} utils.ErrorMessage
+// @Router /internal/log [delete]
+func (i *internal) ResetLogLevel(c *gin.Context) {
+	component := c.Query("component")
+	if !validComponent(c, component) {
+		return
+	}
+	configured, _ := logging.ParseLevel(i.cfg.Log.Level)
+	state := i.logLevels.ResetLevel(component, configured)
+	logging.FromContext(c.Request.Context()).Warnf("Log level of %s reset", orGlobal(component))
+	utils.RespondJSON(c, http.StatusOK, state)
+}
+
+// validComponent rejects the components nothing logs under, a typo would otherwise change nothing.
+func validComponent(c *gin.Context, component string) bool {
+	if component == "" || logging.IsComponent(component) {
+		return true
+	}
+	utils.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("unknown component %q, use one of %s",
+		component, strings.Join(logging.Components(), ", ")))
+	return false
+}
+
+func orGlobal(component string) string {
+	if component == "" {
+		return "all components"
+	}
+	return component
 }
 
-// HealthCheck Checks the health of the service
-// @Summary Health Check
-// @Description Checks the health of the service
+// Live Reports whether the process is alive
+// @Summary Liveness probe
+// @Description Answers as long as the process can serve HTTP, dependencies are not checked
 // @Produce json
 // @Tags Internal
 // @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} utils.ErrorMessage
-// @Router /health [get]
-func (i *internal) HealthCheck(c *gin.Context) {
-	err := i.db.Ping(context.Background())
-	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Database not available")
+// @Router /internal/health/live [get]
+func (i *internal) Live(c *gin.Context) {
+	utils.RespondJSON(c, http.StatusOK, gin.H{"status": health.StatusUp})
+}
+
+// Ready Reports whether the service should receive traffic
+// @Summary Readiness probe
+// @Description Fails with 503 during shutdown or when a critical dependency is down, results are cached briefly
+// @Produce json
+// @Tags Internal
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} utils.ErrorMessage
+// @Router /internal/health/ready [get]
+func (i *internal) Ready(c *gin.Context) {
+	if !i.readiness.IsReady() {
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, constants.SERVICE_SHUTTING_DOWN)
+		return
+	}
+	report := i.healthRegistry.Report(c.Request.Context())
+	if report.Status == health.StatusDown {
+		var failing []string
+		for _, check := range report.Checks {
+			if check.Critical && check.Status == health.StatusDown {
+				failing = append(failing, check.Name)
+			}
+		}
+		utils.ErrorResponse(c, http.StatusServiceUnavailable,
+			fmt.Sprintf(constants.DEPENDENCIES_UNAVAILABLE, strings.Join(failing, ", ")))
 		return
 	}
-	utils.RespondJSON(c, http.StatusOK, gin.H{"status": "up"})
+	utils.RespondJSON(c, http.StatusOK, gin.H{"status": report.Status})
 }
 
-func SetupInternalRoute(router *gin.Engine, internalController InternalController, limiter *rate.Limiter) {
+// HealthDetails Reports the health of every dependency
+// @Summary Detailed health
+// @Description Runs every registered health check and reports its status and latency, with build information. Requires the logs:view permission
+// @Produce json
+// @Tags Internal
+// @Security BearerAuth
+// @Success 200 {object} health.Report
+// @Failure 401 {object} utils.ErrorMessage
+// @Failure 403 {object} utils.ErrorMessage
+// @Failure 503 {object} health.Report
+// @Router /internal/health/details [get]
+func (i *internal) HealthDetails(c *gin.Context) {
+	report := i.healthRegistry.Report(c.Request.Context())
+	statusCode := http.StatusOK
+	if report.Status == health.StatusDown || !i.readiness.IsReady() {
+		statusCode = http.StatusServiceUnavailable
+	}
+	utils.RespondJSON(c, statusCode, report)
+}
+
+// GetConfig Returns the running configuration
+// @Summary Get configuration
+// @Description Returns the configuration the service was started with, secrets are redacted
+// @Produce json
+// @Tags Internal
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} utils.ErrorMessage
+// @Failure 403 {object} utils.ErrorMessage
+// @Router /internal/config [get]
+func (i *internal) GetConfig(c *gin.Context) {
+	utils.RespondJSON(c, http.StatusOK, i.cfg.Redacted())
+}
+
+func SetupInternalRoute(router *gin.Engine, internalController InternalController, limiter *middlewares.RateLimiter,
+	healthLimiter *middlewares.RateLimiter, authMiddleware gin.HandlerFunc) {
 	swagger := router.Group("/swagger")
 
 	swagger.GET("/*any", ginSwagger.WrapHandler(swaggerFiles.Handler,
 		ginSwagger.DefaultModelsExpandDepth(-1)))
 
+	// Probes get their own budget, busy internal endpoints must not make a healthy instance look down
+	healthRoutes := router.Group("/internal/health")
+	healthRoutes.Use(middlewares.RateLimitMiddleware(healthLimiter))
+	// Kept for probes configured before the split, same as /ready
+	healthRoutes.GET("", internalController.Ready)
+	healthRoutes.GET("/live", internalController.Live)
+	healthRoutes.GET("/ready", internalController.Ready)
+	// Details expose file paths and dependency errors, only the probes stay public
+	healthRoutes.GET("/details", authMiddleware, middlewares.RequirePermission(models.PermissionViewLogs),
+		internalController.HealthDetails)
+
 	internalRoutes := router.Group("/internal")
 	internalRoutes.Use(middlewares.RateLimitMiddleware(limiter))
 
 	pprof.Register(internalRoutes, "/pprof")
 	internalRoutes.GET("/metrics", gin.WrapH(promhttp.Handler()))
-	internalRoutes.PUT("/log/:level", internalController.SetLogLevel)
-	internalRoutes.GET("/health", internalController.HealthCheck)
+
+	logRoutes := internalRoutes.Group("/log")
+	logRoutes.Use(authMiddleware, middlewares.RequirePermission(models.PermissionViewLogs))
+	logRoutes.GET("", internalController.GetLogLevel)
+	logRoutes.PUT("/:level", middlewares.RequirePermission(models.PermissionManageLogs), internalController.SetLogLevel)
+	logRoutes.DELETE("", middlewares.RequirePermission(models.PermissionManageLogs), internalController.ResetLogLevel)
+
+	internalRoutes.GET("/config", authMiddleware, middlewares.RequireRole(models.RoleAdmin), internalController.GetConfig)
 }
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"starter/internal/app/controllers/mocks"
+	"starter/internal/app/logging"
+	"starter/internal/app/utils"
+	"starter/internal/config"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newLogLevelRouter() (*gin.Engine, *logging.LevelControl) {
+	gin.SetMode(gin.TestMode)
+	levels := logging.NewLevelControl(logrus.New())
+	controller := &internal{logLevels: levels, cfg: config.Defaults()}
+	router := gin.New()
+	router.PUT("/internal/log/:level", controller.SetLogLevel)
+	router.DELETE("/internal/log", controller.ResetLogLevel)
+	return router, levels
+}
+
+func TestSetLogLevel_Validation(t *testing.T) {
+	router, levels := newLogLevelRouter()
+	cases := []struct {
+		url  string
+		want int
+	}{
+		{"/internal/log/debug?component=repository&ttl=10m", http.StatusOK},
+		{"/internal/log/debug?component=Repository", http.StatusOK},
+		{"/internal/log/debug?component=repositry", http.StatusBadRequest},
+		{"/internal/log/debug?ttl=1ns", http.StatusBadRequest},
+		{"/internal/log/debug?ttl=25h", http.StatusBadRequest},
+		{"/internal/log/verbose", http.StatusBadRequest},
+	}
+	for _, tt := range cases {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, tt.url, nil))
+		assert.Equal(t, tt.want, recorder.Code, tt.url)
+	}
+	assert.NotContains(t, levels.State().Components, "repositry")
+}
+
+func TestResetLogLevel_RejectsUnknownComponents(t *testing.T) {
+	router, _ := newLogLevelRouter()
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/internal/log?component=repositry", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "http, repository, services")
+}
+
+func TestSetupInternalRoute_HealthDetailsNeedAToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := mocks.NewInternalController(t)
+	controller.On("Live", mock.Anything).Return().Run(func(args mock.Arguments) {
+		args.Get(0).(*gin.Context).Status(http.StatusOK)
+	})
+	rejectAll := func(c *gin.Context) { utils.ErrorResponse(c, http.StatusUnauthorized, "no token") }
+	router := gin.New()
+	SetupInternalRoute(router, controller, nil, nil, rejectAll)
+
+	for url, want := range map[string]int{"/internal/health/live": http.StatusOK, "/internal/health/details": http.StatusUnauthorized} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, url, nil))
+		assert.Equal(t, want, recorder.Code, url)
+	}
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"net/http"
+	"starter/internal/app/constants"
+	"starter/internal/app/middlewares"
+	"starter/internal/app/models"
+	"starter/internal/app/services"
+	"starter/internal/app/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockery --name AuthController
+type AuthController interface {
+	Login(c *gin.Context)
+}
+
+type authController struct {
+	authService services.AuthService
+}
+
+func NewAuthController(authService services.AuthService) AuthController {
+	return &authController{authService: authService}
+}
+
+// Login Authenticates a user and issues an access token
+// @Summary Login
+// @Description Checks the user credentials, creates a session and returns a signed access token
+// @Accept json
+// @Produce json
+// @Tags Auth
+// @Param credentials body models.LoginRequestDto true "User credentials"
+// @Success 200 {object} models.LoginResponseDto
+// @Failure 400 {object} utils.ErrorMessage
+// @Failure 401 {object} utils.ErrorMessage
+// @Failure 500 {object} utils.ErrorMessage
+// @Router /auth/login [post]
+func (ac *authController) Login(c *gin.Context) {
+	var loginDto models.LoginRequestDto
+	if err := c.ShouldBindJSON(&loginDto); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, constants.POST_READ_ERROR)
+		return
+	}
+	token, svcErr := ac.authService.Login(c.Request.Context(), &loginDto)
+	if svcErr != nil {
+		utils.ErrorResponse(c, svcErr.StatusCode, svcErr.Message)
+		return
+	}
+	utils.RespondJSON(c, http.StatusOK, token)
+}
+
+func SetupAuthRoute(router *gin.Engine, authController AuthController, limiter *middlewares.RateLimiter, requestTimeout time.Duration) {
+	authRoutes := router.Group("/auth")
+	authRoutes.Use(middlewares.RateLimitMiddleware(limiter))
+	authRoutes.Use(middlewares.TimeoutMiddleware(requestTimeout))
+	authRoutes.POST("/login", authController.Login)
+}
@@ -0,0 +1,32 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuthController is an autogenerated mock type for the AuthController type
+type AuthController struct {
+	mock.Mock
+}
+
+// Login provides a mock function with given fields: c
+func (_m *AuthController) Login(c *gin.Context) {
+	_m.Called(c)
+}
+
+// NewAuthController creates a new instance of AuthController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthController(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuthController {
+	mock := &AuthController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -12,8 +12,33 @@ type InternalController struct {
 	mock.Mock
 }
 
-// HealthCheck provides a mock function with given fields: c
-func (_m *InternalController) HealthCheck(c *gin.Context) {
+// GetConfig provides a mock function with given fields: c
+func (_m *InternalController) GetConfig(c *gin.Context) {
+	_m.Called(c)
+}
+
+// GetLogLevel provides a mock function with given fields: c
+func (_m *InternalController) GetLogLevel(c *gin.Context) {
+	_m.Called(c)
+}
+
+// HealthDetails provides a mock function with given fields: c
+func (_m *InternalController) HealthDetails(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Live provides a mock function with given fields: c
+func (_m *InternalController) Live(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Ready provides a mock function with given fields: c
+func (_m *InternalController) Ready(c *gin.Context) {
+	_m.Called(c)
+}
+
+// ResetLogLevel provides a mock function with given fields: c
+func (_m *InternalController) ResetLogLevel(c *gin.Context) {
 	_m.Called(c)
 }
 
@@ -12,11 +12,41 @@ type UserController struct {
 	mock.Mock
 }
 
+// ChangePassword provides a mock function with given fields: c
+func (_m *UserController) ChangePassword(c *gin.Context) {
+	_m.Called(c)
+}
+
+// CreateUser provides a mock function with given fields: c
+func (_m *UserController) CreateUser(c *gin.Context) {
+	_m.Called(c)
+}
+
+// DeleteUser provides a mock function with given fields: c
+func (_m *UserController) DeleteUser(c *gin.Context) {
+	_m.Called(c)
+}
+
 // GetUserByEmail provides a mock function with given fields: c
 func (_m *UserController) GetUserByEmail(c *gin.Context) {
 	_m.Called(c)
 }
 
+// GetUserByID provides a mock function with given fields: c
+func (_m *UserController) GetUserByID(c *gin.Context) {
+	_m.Called(c)
+}
+
+// ListUsers provides a mock function with given fields: c
+func (_m *UserController) ListUsers(c *gin.Context) {
+	_m.Called(c)
+}
+
+// UpdateSelf provides a mock function with given fields: c
+func (_m *UserController) UpdateSelf(c *gin.Context) {
+	_m.Called(c)
+}
+
 // NewUserController creates a new instance of UserController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserController(t interface {
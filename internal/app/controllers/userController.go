@@ -1,23 +1,31 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
 	"starter/internal/app/constants"
 	"starter/internal/app/middlewares"
+	"starter/internal/app/models"
 	"starter/internal/app/services"
 	"starter/internal/app/utils"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
 )
 
 //go:generate mockery --name UserController
 type UserController interface {
 	GetUserByEmail(c *gin.Context)
+	GetUserByID(c *gin.Context)
+	ListUsers(c *gin.Context)
+	CreateUser(c *gin.Context)
+	UpdateSelf(c *gin.Context)
+	ChangePassword(c *gin.Context)
+	DeleteUser(c *gin.Context)
 }
 
 type userController struct {
-	aesKey      string
 	userService services.UserService
 }
 
@@ -26,9 +34,13 @@ type userController struct {
 // @Description Gets the user details by Email
 // @Produce json
 // @Tags User
+// @Security BearerAuth
 // @Param email path string true "User email"
 // @Success 200 {object} models.UserResponseDto
 // @Failure 400 {object} utils.ErrorMessage
+// @Failure 401 {object} utils.ErrorMessage
+// @Failure 403 {object} utils.ErrorMessage
+// @Failure 404 {object} utils.ErrorMessage
 // @Failure 500 {object} utils.ErrorMessage
 // @Router /user/{email} [get]
 func (uc *userController) GetUserByEmail(c *gin.Context) {
@@ -37,7 +49,7 @@ func (uc *userController) GetUserByEmail(c *gin.Context) {
 		utils.ErrorResponse(c, http.StatusBadRequest, constants.INVALID_ID)
 		return
 	}
-	user, svcErr := uc.userService.GetUserByEmail(email)
+	user, svcErr := uc.userService.GetUserByEmail(c.Request.Context(), email)
 	if svcErr != nil {
 		utils.ErrorResponse(c, svcErr.StatusCode, svcErr.Message)
 		return
@@ -45,16 +57,219 @@ func (uc *userController) GetUserByEmail(c *gin.Context) {
 	utils.RespondJSON(c, http.StatusOK, user)
 }
 
+// CreateUser Registers a new user
+// @Summary Registers a new user
+// @Description Validates the request, stores a salted password hash and returns the created user
+// @Accept json
+// @Produce json
+// @Tags User
+// @Security BearerAuth
+// @Param user body models.UserRequestDto true "User details"
+// @Success 201 {object} models.UserResponseDto
+// @Failure 400 {object} utils.ErrorMessage
+// @Failure 401 {object} utils.ErrorMessage
+// @Failure 403 {object} utils.ErrorMessage
+// @Failure 409 {object} utils.ErrorMessage
+// @Failure 500 {object} utils.ErrorMessage
+// @Router /user [post]
+func (uc *userController) CreateUser(c *gin.Context) {
+	var userDto models.UserRequestDto
+	if err := c.ShouldBindJSON(&userDto); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, constants.POST_READ_ERROR)
+		return
+	}
+	user, svcErr := uc.userService.CreateUser(c.Request.Context(), &userDto)
+	if svcErr != nil {
+		utils.ErrorResponse(c, svcErr.StatusCode, svcErr.Message)
+		return
+	}
+	utils.RespondJSON(c, http.StatusCreated, user)
+}
+
+// GetUserByID Gets the user details by ID
+// @Summary Gets the user details by ID
+// @Description Gets the user details by ID
+// @Produce json
+// @Tags User
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.UserResponseDto
+// @Failure 400 {object} utils.ErrorMessage
+// @Failure 401 {object} utils.ErrorMessage
+// @Failure 403 {object} utils.ErrorMessage
+// @Failure 404 {object} utils.ErrorMessage
+// @Failure 500 {object} utils.ErrorMessage
+// @Router /user/id/{id} [get]
+func (uc *userController) GetUserByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, constants.INVALID_ID)
+		return
+	}
+	user, svcErr := uc.userService.GetUserByID(c.Request.Context(), id)
+	if svcErr != nil {
+		utils.ErrorResponse(c, svcErr.StatusCode, svcErr.Message)
+		return
+	}
+	utils.RespondJSON(c, http.StatusOK, user)
+}
+
+// ListUsers Lists users page by page
+// @Summary Lists users page by page
+// @Description Lists users with pagination, sorting and optional filters
+// @Produce json
+// @Tags User
+// @Security BearerAuth
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Page size, defaults to 10, at most 100"
+// @Param sort query string false "Sort column. Emails and names are encrypted, sorting by userEmailId or the names was removed and answers 400" Enums(id, inserted_at, updated_at, userRole)
+// @Param sortDesc query bool false "Sort descending"
+// @Param role query string false "Only users with this role"
+// @Param email query string false "Only the user with exactly this email. Prefix search was removed with email encryption, a partial email answers 400"
+// @Param createdAfter query string false "Only users created after this RFC3339 timestamp or YYYY-MM-DD date"
+// @Success 200 {object} utils.Pagination{rows=[]models.UserResponseDto}
+// @Failure 400 {object} utils.ErrorMessage
+// @Failure 401 {object} utils.ErrorMessage
+// @Failure 403 {object} utils.ErrorMessage
+// @Failure 500 {object} utils.ErrorMessage
+// @Router /user [get]
+func (uc *userController) ListUsers(c *gin.Context) {
+	if sort := c.Query("sort"); utils.StringContains(models.EncryptedUserSortFields, sort) {
+		utils.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf(constants.ENCRYPTED_SORT_FIELD, sort))
+		return
+	}
+	pagination, err := utils.PaginateQueryExtractor(c, models.UserSortFields)
+	if err != nil {
+		utils.ErrorResponse(c, err.StatusCode, err.Message)
+		return
+	}
+	filter, err := models.NewUserFilter(c.Query("role"), c.Query("email"), c.Query("createdAfter"))
+	if err != nil {
+		utils.ErrorResponse(c, err.StatusCode, err.Message)
+		return
+	}
+	users, svcErr := uc.userService.ListUsers(c.Request.Context(), pagination, filter)
+	if svcErr != nil {
+		utils.ErrorResponse(c, svcErr.StatusCode, svcErr.Message)
+		return
+	}
+	utils.RespondJSON(c, http.StatusOK, users)
+}
+
+// UpdateSelf Updates the details of the logged in user
+// @Summary Updates the details of the logged in user
+// @Description Updates the email, display name and names of the logged in user
+// @Accept json
+// @Produce json
+// @Tags User
+// @Security BearerAuth
+// @Param user body models.UserUpdateDto true "User details"
+// @Success 200 {object} models.UserResponseDto
+// @Failure 400 {object} utils.ErrorMessage
+// @Failure 401 {object} utils.ErrorMessage
+// @Failure 409 {object} utils.ErrorMessage
+// @Failure 500 {object} utils.ErrorMessage
+// @Router /user/me [put]
+func (uc *userController) UpdateSelf(c *gin.Context) {
+	currentUser, ok := middlewares.GetAuthUser(c)
+	if !ok {
+		utils.ErrorResponse(c, http.StatusUnauthorized, constants.MISSING_AUTH_TOKEN)
+		return
+	}
+	var updateDto models.UserUpdateDto
+	if err := c.ShouldBindJSON(&updateDto); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, constants.POST_READ_ERROR)
+		return
+	}
+	user, svcErr := uc.userService.UpdateSelf(c.Request.Context(), currentUser, &updateDto)
+	if svcErr != nil {
+		utils.ErrorResponse(c, svcErr.StatusCode, svcErr.Message)
+		return
+	}
+	utils.RespondJSON(c, http.StatusOK, user)
+}
+
+// ChangePassword Changes the password of the logged in user
+// @Summary Changes the password of the logged in user
+// @Description Checks the old password, stores the new one under a new salt and revokes all sessions
+// @Accept json
+// @Produce json
+// @Tags User
+// @Security BearerAuth
+// @Param password body models.PasswordUpdateDto true "Old and new password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} utils.ErrorMessage
+// @Failure 401 {object} utils.ErrorMessage
+// @Failure 500 {object} utils.ErrorMessage
+// @Router /user/me/password [put]
+func (uc *userController) ChangePassword(c *gin.Context) {
+	currentUser, ok := middlewares.GetAuthUser(c)
+	if !ok {
+		utils.ErrorResponse(c, http.StatusUnauthorized, constants.MISSING_AUTH_TOKEN)
+		return
+	}
+	var passwordDto models.PasswordUpdateDto
+	if err := c.ShouldBindJSON(&passwordDto); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, constants.POST_READ_ERROR)
+		return
+	}
+	if svcErr := uc.userService.ChangePassword(c.Request.Context(), currentUser, &passwordDto); svcErr != nil {
+		utils.ErrorResponse(c, svcErr.StatusCode, svcErr.Message)
+		return
+	}
+	utils.RespondJSON(c, http.StatusOK, gin.H{"message": "Password updated, please log in again"})
+}
+
+// DeleteUser Deletes a user by Email
+// @Summary Deletes a user by Email
+// @Description Deletes a user by Email, admin only
+// @Produce json
+// @Tags User
+// @Security BearerAuth
+// @Param email path string true "User email"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} utils.ErrorMessage
+// @Failure 401 {object} utils.ErrorMessage
+// @Failure 403 {object} utils.ErrorMessage
+// @Failure 404 {object} utils.ErrorMessage
+// @Failure 500 {object} utils.ErrorMessage
+// @Router /user/{email} [delete]
+func (uc *userController) DeleteUser(c *gin.Context) {
+	currentUser, ok := middlewares.GetAuthUser(c)
+	if !ok {
+		utils.ErrorResponse(c, http.StatusUnauthorized, constants.MISSING_AUTH_TOKEN)
+		return
+	}
+	email := c.Param("email")
+	if len(email) == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, constants.INVALID_EMAILID)
+		return
+	}
+	if svcErr := uc.userService.DeleteUser(c.Request.Context(), currentUser, email); svcErr != nil {
+		utils.ErrorResponse(c, svcErr.StatusCode, svcErr.Message)
+		return
+	}
+	utils.RespondJSON(c, http.StatusOK, gin.H{"message": "User deleted"})
+}
+
 func NewUserController(userService services.UserService) UserController {
-	aesKey := utils.GetEnvAsString("AES_KEY", "1234567812345678")
-	return &userController{aesKey: aesKey,
-		userService: userService}
+	return &userController{userService: userService}
 }
 
-func SetupUserRoute(router *gin.Engine, userController UserController,
-	limiter *rate.Limiter, ) {
+func SetupUserRoute(router *gin.Engine, userController UserController, ipLimiter *middlewares.RateLimiter,
+	limiter *middlewares.RateLimiter, authMiddleware gin.HandlerFunc, requestTimeout time.Duration) {
 	userRoutes := router.Group("/user")
+	// Limited per IP before authentication, which would otherwise reject invalid tokens unlimited,
+	// then after it so that each user has a budget of their own
+	userRoutes.Use(middlewares.ClientIPRateLimitMiddleware(ipLimiter))
+	userRoutes.Use(authMiddleware)
 	userRoutes.Use(middlewares.RateLimitMiddleware(limiter))
-	userRoutes.Use(middlewares.TimeoutMiddleware())
-	userRoutes.GET("/:email", userController.GetUserByEmail)
+	userRoutes.Use(middlewares.TimeoutMiddleware(requestTimeout))
+	userRoutes.GET("", middlewares.RequirePermission(models.PermissionViewUsers), userController.ListUsers)
+	userRoutes.POST("", middlewares.RequirePermission(models.PermissionManageUsers), userController.CreateUser)
+	userRoutes.GET("/id/:id", middlewares.RequirePermission(models.PermissionViewUsers), userController.GetUserByID)
+	userRoutes.GET("/:email", middlewares.RequirePermission(models.PermissionViewUsers), userController.GetUserByEmail)
+	userRoutes.PUT("/me", userController.UpdateSelf)
+	userRoutes.PUT("/me/password", userController.ChangePassword)
+	userRoutes.DELETE("/:email", middlewares.RequireRole(models.RoleAdmin), userController.DeleteUser)
 }
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"starter/internal/app/models"
+	"starter/internal/app/services/mocks"
+	"starter/internal/app/utils"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListUsers_RefusesWhatEncryptionRemoved(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userService := mocks.NewUserService(t)
+	userService.On("ListUsers", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *models.UserFilter) bool {
+		return filter.Email == "jane@example.com"
+	})).Return(&utils.Pagination{}, nil)
+	router := gin.New()
+	router.GET("/user", NewUserController(userService).ListUsers)
+
+	cases := []struct {
+		url     string
+		want    int
+		message string
+	}{
+		{"/user?email=jane@example.com", http.StatusOK, ""},
+		{"/user?email=jane", http.StatusBadRequest, "cannot be searched by prefix"},
+		{"/user?email=Jane%20%3Cjane@example.com%3E", http.StatusBadRequest, "cannot be searched by prefix"},
+		{"/user?sort=userEmailId", http.StatusBadRequest, "cannot be sorted by userEmailId"},
+		{"/user?sort=name", http.StatusBadRequest, "cannot be sorted by name"},
+		{"/user?sort=unknown", http.StatusBadRequest, "Invalid sort field"},
+	}
+	for _, tt := range cases {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.url, nil))
+		assert.Equal(t, tt.want, recorder.Code, tt.url)
+		assert.Contains(t, recorder.Body.String(), tt.message, tt.url)
+	}
+}
@@ -0,0 +1,138 @@
+// Package correlation carries the request ID and the W3C trace context of a request through
+// its context.Context, so that logs and outbound calls can be tied back to it across services.
+package correlation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	RequestIDHeader   = "X-Request-ID"
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+// requestIDPattern accepts the IDs of common gateways (UUIDs, ULIDs, AWS and nginx IDs) while
+// keeping anything that could break a log line or a header out.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:=+/-]{0,127}$`)
+
+// maxTracestateLength is the length beyond which the W3C spec allows dropping tracestate.
+const maxTracestateLength = 512
+
+func ValidRequestID(id string) bool {
+	return requestIDPattern.MatchString(id)
+}
+
+// TraceParent is a parsed W3C traceparent header, "00-<trace id>-<parent id>-<flags>".
+type TraceParent struct {
+	TraceID  string
+	ParentID string
+	Flags    string
+}
+
+// ParseTraceparent returns false for headers that do not follow the spec, including the
+// all zero IDs it forbids. Future versions are read as version 00, as the spec asks.
+func ParseTraceparent(header string) (TraceParent, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || (parts[0] == "00" && len(parts) != 4) || parts[0] == "ff" {
+		return TraceParent{}, false
+	}
+	version, traceID, parentID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isLowerHex(version, 2) || !isLowerHex(traceID, 32) || !isLowerHex(parentID, 16) || !isLowerHex(flags, 2) {
+		return TraceParent{}, false
+	}
+	if traceID == strings.Repeat("0", 32) || parentID == strings.Repeat("0", 16) {
+		return TraceParent{}, false
+	}
+	return TraceParent{TraceID: traceID, ParentID: parentID, Flags: flags}, true
+}
+
+func isLowerHex(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, r := range value {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func (t TraceParent) String() string {
+	return "00-" + t.TraceID + "-" + t.ParentID + "-" + t.Flags
+}
+
+// NewTraceParent starts a new sampled trace.
+func NewTraceParent() TraceParent {
+	return TraceParent{TraceID: randomHex(16), ParentID: randomHex(8), Flags: "01"}
+}
+
+// Child returns the traceparent of a new span within the same trace.
+func (t TraceParent) Child() TraceParent {
+	return TraceParent{TraceID: t.TraceID, ParentID: randomHex(8), Flags: t.Flags}
+}
+
+func randomHex(size int) string {
+	for {
+		raw := make([]byte, size)
+		_, _ = rand.Read(raw)
+		if id := hex.EncodeToString(raw); id != strings.Repeat("0", size*2) {
+			return id
+		}
+	}
+}
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	traceKey
+)
+
+type trace struct {
+	parent TraceParent
+	state  string
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID stored by WithRequestID, or "" outside of a request.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithTrace stores the span of the current request and the vendor tracestate to pass on.
+func WithTrace(ctx context.Context, parent TraceParent, state string) context.Context {
+	if len(state) > maxTracestateLength {
+		state = ""
+	}
+	return context.WithValue(ctx, traceKey, trace{parent: parent, state: state})
+}
+
+func Trace(ctx context.Context) (TraceParent, string, bool) {
+	t, ok := ctx.Value(traceKey).(trace)
+	return t.parent, t.state, ok
+}
+
+// Inject adds the correlation headers of ctx to an outbound request. The traceparent names the
+// span of the current request as parent of the callee.
+func Inject(ctx context.Context, header http.Header) {
+	if requestID := RequestID(ctx); requestID != "" {
+		header.Set(RequestIDHeader, requestID)
+	}
+	if parent, state, ok := Trace(ctx); ok {
+		header.Set(TraceparentHeader, parent.String())
+		if state != "" {
+			header.Set(TracestateHeader, state)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package correlation
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidRequestID(t *testing.T) {
+	for _, id := range []string{"0190b3a2-7c4e-7d2a-9f1e-3b5c6d7e8f90", "Root=1-67891233-abcdef012345678912345678", "req_42"} {
+		assert.True(t, ValidRequestID(id), id)
+	}
+	for _, id := range []string{"", "-leading-dash", "has space", "line\nbreak", strings.Repeat("a", 129)} {
+		assert.False(t, ValidRequestID(id), id)
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	parent, ok := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.True(t, ok)
+	assert.Equal(t, TraceParent{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", ParentID: "00f067aa0ba902b7", Flags: "01"}, parent)
+
+	_, ok = ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future")
+	assert.True(t, ok, "later versions may append fields")
+
+	for _, header := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		_, ok := ParseTraceparent(header)
+		assert.False(t, ok, header)
+	}
+}
+
+func TestChild_KeepsTheTrace(t *testing.T) {
+	parent := NewTraceParent()
+	child := parent.Child()
+	assert.Equal(t, parent.TraceID, child.TraceID)
+	assert.NotEqual(t, parent.ParentID, child.ParentID)
+	_, ok := ParseTraceparent(child.String())
+	assert.True(t, ok)
+}
+
+func TestInject(t *testing.T) {
+	header := http.Header{}
+	Inject(context.Background(), header)
+	assert.Empty(t, header)
+
+	parent, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := WithTrace(WithRequestID(context.Background(), "req-1"), parent, "vendor=abc")
+	Inject(ctx, header)
+	assert.Equal(t, "req-1", header.Get(RequestIDHeader))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", header.Get(TraceparentHeader))
+	assert.Equal(t, "vendor=abc", header.Get(TracestateHeader))
+}
@@ -0,0 +1,126 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"starter/internal/config"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// prefix marks encrypted values, "enc:<key id>:<base64 of nonce and ciphertext>". Values
+// without it were written before encryption was enabled and are read as plaintext.
+const prefix = "enc:"
+
+var ErrUnknownKey = errors.New("value was encrypted with a key that is not configured")
+
+//go:generate mockery --name FieldCipher
+type FieldCipher interface {
+	// Encrypt seals the value with the active key, the empty string stays empty.
+	Encrypt(plaintext string) (string, error)
+	Decrypt(value string) (string, error)
+	// BlindIndex returns a keyed hash of the normalised value, stable across AES key
+	// rotations, to look encrypted values up by equality.
+	BlindIndex(value string) string
+	// NeedsRotation reports whether the value is plaintext or sealed with an older key.
+	NeedsRotation(value string) bool
+}
+
+type aesFieldCipher struct {
+	activeID string
+	aeads    map[string]cipher.AEAD
+	indexKey []byte
+}
+
+// NewFieldCipher builds the cipher of the configured AES keys, exiting on invalid keys
+// like ConnectDB does on an unreachable database. The configuration is validated on load,
+// so this only happens when it was built by hand.
+func NewFieldCipher(securityConfig config.SecurityConfig) FieldCipher {
+	fieldCipher, err := New(securityConfig.AESKeyring(), securityConfig.BlindIndexKeyBytes())
+	if err != nil {
+		logrus.Fatalf("Unable to set up field encryption: %v", err)
+		return nil
+	}
+	return fieldCipher
+}
+
+func New(keyring config.Keyring, indexKey []byte) (FieldCipher, error) {
+	if len(indexKey) == 0 {
+		return nil, errors.New("the blind index key is empty")
+	}
+	aeads := make(map[string]cipher.AEAD, len(keyring.Keys))
+	for id, key := range keyring.Keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		aeads[id] = aead
+	}
+	if _, ok := aeads[keyring.ActiveID]; !ok {
+		return nil, fmt.Errorf("active key %q is not configured", keyring.ActiveID)
+	}
+	return &aesFieldCipher{activeID: keyring.ActiveID, aeads: aeads, indexKey: indexKey}, nil
+}
+
+func (c *aesFieldCipher) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	aead := c.aeads[c.activeID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	// The key ID is authenticated so that a value cannot be relabelled with another key
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(c.activeID))
+	return prefix + c.activeID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *aesFieldCipher) Decrypt(value string) (string, error) {
+	rest, encrypted := strings.CutPrefix(value, prefix)
+	if !encrypted {
+		return value, nil
+	}
+	keyID, encoded, found := strings.Cut(rest, ":")
+	if !found {
+		return "", errors.New("encrypted value has no key ID")
+	}
+	aead, ok := c.aeads[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("encrypted value is malformed")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("encrypted value cannot be authenticated with key %s", keyID)
+	}
+	return string(plaintext), nil
+}
+
+func (c *aesFieldCipher) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *aesFieldCipher) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	return !strings.HasPrefix(value, prefix+c.activeID+":")
+}
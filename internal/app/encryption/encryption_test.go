@@ -0,0 +1,81 @@
+package encryption
+
+import (
+	"starter/internal/config"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCipher(t *testing.T, list, activeID string) FieldCipher {
+	keyring, err := config.ParseKeyring("", list, activeID)
+	assert.NoError(t, err)
+	fieldCipher, err := New(keyring, []byte("index-key"))
+	assert.NoError(t, err)
+	return fieldCipher
+}
+
+func TestFieldCipher_RoundTrip(t *testing.T) {
+	fieldCipher := newCipher(t, "k1:aaaaaaaaaaaaaaaa", "")
+	ciphertext, err := fieldCipher.Encrypt("Jane")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ciphertext, "enc:k1:"))
+
+	again, _ := fieldCipher.Encrypt("Jane")
+	assert.NotEqual(t, ciphertext, again, "nonces must differ")
+
+	plaintext, err := fieldCipher.Decrypt(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane", plaintext)
+
+	empty, _ := fieldCipher.Encrypt("")
+	assert.Equal(t, "", empty)
+}
+
+func TestFieldCipher_PlaintextPassesThrough(t *testing.T) {
+	fieldCipher := newCipher(t, "k1:aaaaaaaaaaaaaaaa", "")
+	plaintext, err := fieldCipher.Decrypt("written before encryption")
+	assert.NoError(t, err)
+	assert.Equal(t, "written before encryption", plaintext)
+	assert.True(t, fieldCipher.NeedsRotation("written before encryption"))
+}
+
+func TestFieldCipher_Rotation(t *testing.T) {
+	oldCipher := newCipher(t, "k1:aaaaaaaaaaaaaaaa", "")
+	ciphertext, _ := oldCipher.Encrypt("Jane")
+
+	rotated := newCipher(t, "k1:aaaaaaaaaaaaaaaa,k2:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", "k2")
+	assert.True(t, rotated.NeedsRotation(ciphertext))
+	plaintext, err := rotated.Decrypt(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane", plaintext)
+	assert.Equal(t, oldCipher.BlindIndex("jane@example.com"), rotated.BlindIndex("jane@example.com"))
+
+	reencrypted, _ := rotated.Encrypt(plaintext)
+	assert.False(t, rotated.NeedsRotation(reencrypted))
+
+	withoutOldKey := newCipher(t, "k2:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", "")
+	_, err = withoutOldKey.Decrypt(ciphertext)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestFieldCipher_RejectsTamperedValues(t *testing.T) {
+	fieldCipher := newCipher(t, "k1:aaaaaaaaaaaaaaaa,k2:cccccccccccccccc", "")
+	ciphertext, _ := fieldCipher.Encrypt("Jane")
+
+	// Relabelling the key ID must fail authentication even if both keys are known
+	_, err := fieldCipher.Decrypt(strings.Replace(ciphertext, "enc:k1:", "enc:k2:", 1))
+	assert.Error(t, err)
+
+	tampered := ciphertext[:len(ciphertext)-2] + "AA"
+	_, err = fieldCipher.Decrypt(tampered)
+	assert.Error(t, err)
+}
+
+func TestFieldCipher_BlindIndexIsNormalised(t *testing.T) {
+	fieldCipher := newCipher(t, "k1:aaaaaaaaaaaaaaaa", "")
+	assert.Equal(t, fieldCipher.BlindIndex("jane@example.com"), fieldCipher.BlindIndex(" Jane@Example.COM "))
+	assert.NotEqual(t, fieldCipher.BlindIndex("jane@example.com"), fieldCipher.BlindIndex("john@example.com"))
+	assert.Len(t, fieldCipher.BlindIndex("jane@example.com"), 64)
+}
@@ -0,0 +1,116 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// FieldCipher is an autogenerated mock type for the FieldCipher type
+type FieldCipher struct {
+	mock.Mock
+}
+
+// BlindIndex provides a mock function with given fields: value
+func (_m *FieldCipher) BlindIndex(value string) string {
+	ret := _m.Called(value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BlindIndex")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(value)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Decrypt provides a mock function with given fields: value
+func (_m *FieldCipher) Decrypt(value string) (string, error) {
+	ret := _m.Called(value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Decrypt")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(value)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(value)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(value)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Encrypt provides a mock function with given fields: plaintext
+func (_m *FieldCipher) Encrypt(plaintext string) (string, error) {
+	ret := _m.Called(plaintext)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Encrypt")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(plaintext)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(plaintext)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(plaintext)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NeedsRotation provides a mock function with given fields: value
+func (_m *FieldCipher) NeedsRotation(value string) bool {
+	ret := _m.Called(value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NeedsRotation")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(value)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// NewFieldCipher creates a new instance of FieldCipher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFieldCipher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FieldCipher {
+	mock := &FieldCipher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,140 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"starter/internal/app/services"
+	"starter/internal/config"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NewDefaultRegistry registers the database, pool, disk and, when a partner URL is configured,
+// partner checkers.
+func NewDefaultRegistry(db config.DBPool, restCaller services.RestCaller, healthConfig config.HealthConfig,
+	logConfig config.LogConfig) Registry {
+	registry := NewRegistry(healthConfig.CacheTTL, healthConfig.CheckTimeout)
+	registry.Register(NewDatabaseChecker(db))
+	registry.Register(NewPoolChecker(db, float64(healthConfig.PoolSaturationPercent)/100))
+	registry.Register(NewDiskChecker(logConfig.File, uint64(healthConfig.DiskMinFreeMB)<<20))
+	if healthConfig.PartnerURL != "" {
+		registry.Register(NewPartnerChecker(restCaller, healthConfig.PartnerURL))
+	}
+	return registry
+}
+
+type databaseChecker struct {
+	db config.DBPool
+}
+
+// NewDatabaseChecker pings the database, it is critical.
+func NewDatabaseChecker(db config.DBPool) Checker {
+	return &databaseChecker{db: db}
+}
+
+func (d *databaseChecker) Name() string   { return "database" }
+func (d *databaseChecker) Critical() bool { return true }
+
+func (d *databaseChecker) Check(ctx context.Context) Outcome {
+	if err := d.db.Ping(ctx); err != nil {
+		return Outcome{Status: StatusDown, Message: fmt.Sprintf("ping failed: %v", err)}
+	}
+	return Outcome{Status: StatusUp}
+}
+
+// poolStater is implemented by *pgxpool.Pool but not by the DBPool mocks.
+type poolStater interface {
+	Stat() *pgxpool.Stat
+}
+
+type poolChecker struct {
+	db         config.DBPool
+	saturation float64
+}
+
+// NewPoolChecker reports the pool as degraded once the share of acquired connections reaches saturation.
+func NewPoolChecker(db config.DBPool, saturation float64) Checker {
+	return &poolChecker{db: db, saturation: saturation}
+}
+
+func (p *poolChecker) Name() string   { return "database_pool" }
+func (p *poolChecker) Critical() bool { return false }
+
+func (p *poolChecker) Check(context.Context) Outcome {
+	stater, ok := p.db.(poolStater)
+	if !ok {
+		return Outcome{Status: StatusUp, Message: "pool statistics not available"}
+	}
+	stat := stater.Stat()
+	details := map[string]interface{}{
+		"acquired":          stat.AcquiredConns(),
+		"idle":              stat.IdleConns(),
+		"total":             stat.TotalConns(),
+		"max":               stat.MaxConns(),
+		"emptyAcquireCount": stat.EmptyAcquireCount(),
+	}
+	if stat.MaxConns() > 0 && float64(stat.AcquiredConns())/float64(stat.MaxConns()) >= p.saturation {
+		return Outcome{Status: StatusDegraded, Message: "connection pool is saturated", Details: details}
+	}
+	return Outcome{Status: StatusUp, Details: details}
+}
+
+type partnerChecker struct {
+	restCaller services.RestCaller
+	url        string
+}
+
+// NewPartnerChecker expects a 2xx from the partner URL. The partner is not critical: the service
+// still answers its own requests while it is away.
+func NewPartnerChecker(restCaller services.RestCaller, url string) Checker {
+	return &partnerChecker{restCaller: restCaller, url: url}
+}
+
+func (p *partnerChecker) Name() string   { return "partner" }
+func (p *partnerChecker) Critical() bool { return false }
+
+func (p *partnerChecker) Check(ctx context.Context) Outcome {
+	resp, err := p.restCaller.GetWithContext(ctx, p.url)
+	if err != nil {
+		return Outcome{Status: StatusDown, Message: fmt.Sprintf("partner unreachable: %v", err)}
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	details := map[string]interface{}{"statusCode": resp.StatusCode}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return Outcome{Status: StatusDown, Message: "partner returned " + resp.Status, Details: details}
+	}
+	return Outcome{Status: StatusUp, Details: details}
+}
+
+type diskChecker struct {
+	path    string
+	minFree uint64
+}
+
+// NewDiskChecker reports degraded when the filesystem holding path has less than minFree bytes left.
+func NewDiskChecker(path string, minFree uint64) Checker {
+	return &diskChecker{path: path, minFree: minFree}
+}
+
+func (d *diskChecker) Name() string   { return "disk" }
+func (d *diskChecker) Critical() bool { return false }
+
+func (d *diskChecker) Check(context.Context) Outcome {
+	dir, err := filepath.Abs(filepath.Dir(d.path))
+	if err != nil {
+		return Outcome{Status: StatusDegraded, Message: err.Error()}
+	}
+	free, err := freeBytes(dir)
+	if err != nil {
+		return Outcome{Status: StatusDegraded, Message: fmt.Sprintf("cannot read free space of %s: %v", dir, err)}
+	}
+	details := map[string]interface{}{"path": dir, "freeBytes": free, "minFreeBytes": d.minFree}
+	if free < d.minFree {
+		return Outcome{Status: StatusDegraded, Message: "low disk space", Details: details}
+	}
+	return Outcome{Status: StatusUp, Details: details}
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !freebsd
+
+package health
+
+import "errors"
+
+func freeBytes(string) (uint64, error) {
+	return 0, errors.New("free space is not reported on this platform")
+}
@@ -0,0 +1,47 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// Readiness is an autogenerated mock type for the Readiness type
+type Readiness struct {
+	mock.Mock
+}
+
+// IsReady provides a mock function with given fields:
+func (_m *Readiness) IsReady() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsReady")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// SetReady provides a mock function with given fields: ready
+func (_m *Readiness) SetReady(ready bool) {
+	_m.Called(ready)
+}
+
+// NewReadiness creates a new instance of Readiness. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReadiness(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Readiness {
+	mock := &Readiness{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,30 @@
+package health
+
+import "sync/atomic"
+
+// Readiness tells load balancers whether this instance should receive traffic. It starts
+// ready and is flipped off when shutdown begins, while in-flight requests drain.
+//
+//go:generate mockery --name Readiness
+type Readiness interface {
+	SetReady(ready bool)
+	IsReady() bool
+}
+
+type readiness struct {
+	ready atomic.Bool
+}
+
+func NewReadiness() Readiness {
+	r := &readiness{}
+	r.ready.Store(true)
+	return r
+}
+
+func (r *readiness) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+func (r *readiness) IsReady() bool {
+	return r.ready.Load()
+}
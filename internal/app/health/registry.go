@@ -0,0 +1,147 @@
+package health
+
+import (
+	"context"
+	"starter/internal/app/buildinfo"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+// Outcome is what a Checker reports about its dependency.
+type Outcome struct {
+	Status  Status
+	Message string
+	Details map[string]interface{}
+}
+
+// Checker probes one dependency. A down critical checker makes the service not ready,
+// other checkers only degrade the reported status.
+type Checker interface {
+	Name() string
+	Critical() bool
+	Check(ctx context.Context) Outcome
+}
+
+type CheckResult struct {
+	Name      string                 `json:"name"`
+	Status    Status                 `json:"status"`
+	Critical  bool                   `json:"critical"`
+	Message   string                 `json:"message,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	LatencyMs float64                `json:"latencyMs"`
+	CheckedAt time.Time              `json:"checkedAt"`
+}
+
+type Report struct {
+	Status Status         `json:"status"`
+	Uptime string         `json:"uptime"`
+	Build  buildinfo.Info `json:"build"`
+	Checks []CheckResult  `json:"checks"`
+}
+
+// Registry runs the registered checkers and caches their results for cacheTTL, so that
+// frequent probes do not hammer the dependencies.
+//
+//go:generate mockery --name Registry
+type Registry interface {
+	Register(checker Checker)
+	Report(ctx context.Context) Report
+}
+
+type entry struct {
+	checker Checker
+	// mu serialises runs of the checker, concurrent probes wait for the run in progress
+	mu     sync.Mutex
+	result CheckResult
+}
+
+type registry struct {
+	mu           sync.RWMutex
+	entries      []*entry
+	cacheTTL     time.Duration
+	checkTimeout time.Duration
+	startedAt    time.Time
+}
+
+func NewRegistry(cacheTTL, checkTimeout time.Duration) Registry {
+	return &registry{cacheTTL: cacheTTL, checkTimeout: checkTimeout, startedAt: time.Now()}
+}
+
+func (r *registry) Register(checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, &entry{checker: checker})
+}
+
+// Report runs the checkers concurrently, reusing results younger than the cache TTL.
+// Checks do not inherit the cancellation of ctx so that an aborted probe cannot cache a failure.
+func (r *registry) Report(ctx context.Context) Report {
+	r.mu.RLock()
+	entries := make([]*entry, len(r.entries))
+	copy(entries, r.entries)
+	r.mu.RUnlock()
+
+	results := make([]CheckResult, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func(i int, e *entry) {
+			defer wg.Done()
+			results[i] = r.run(context.WithoutCancel(ctx), e)
+		}(i, e)
+	}
+	wg.Wait()
+
+	return Report{
+		Status: aggregate(results),
+		Uptime: time.Since(r.startedAt).Round(time.Second).String(),
+		Build:  buildinfo.Get(),
+		Checks: results,
+	}
+}
+
+func (r *registry) run(ctx context.Context, e *entry) CheckResult {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.result.CheckedAt.IsZero() && time.Since(e.result.CheckedAt) < r.cacheTTL {
+		return e.result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.checkTimeout)
+	defer cancel()
+	start := time.Now()
+	outcome := e.checker.Check(ctx)
+	e.result = CheckResult{
+		Name:      e.checker.Name(),
+		Status:    outcome.Status,
+		Critical:  e.checker.Critical(),
+		Message:   outcome.Message,
+		Details:   outcome.Details,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt: start.UTC(),
+	}
+	return e.result
+}
+
+// aggregate is down when a critical check is down, degraded when any other check is not up.
+func aggregate(results []CheckResult) Status {
+	status := StatusUp
+	for _, result := range results {
+		if result.Status == StatusUp {
+			continue
+		}
+		if result.Critical && result.Status == StatusDown {
+			return StatusDown
+		}
+		status = StatusDegraded
+	}
+	return status
+}
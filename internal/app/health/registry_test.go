@@ -0,0 +1,84 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"starter/internal/config/mocks"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type stubChecker struct {
+	name     string
+	critical bool
+	status   Status
+	calls    atomic.Int32
+}
+
+func (s *stubChecker) Name() string   { return s.name }
+func (s *stubChecker) Critical() bool { return s.critical }
+func (s *stubChecker) Check(context.Context) Outcome {
+	s.calls.Add(1)
+	return Outcome{Status: s.status}
+}
+
+func Test_Report_Aggregates(t *testing.T) {
+	cases := []struct {
+		name     string
+		checkers []*stubChecker
+		want     Status
+	}{
+		{"all up", []*stubChecker{{name: "a", critical: true, status: StatusUp}, {name: "b", status: StatusUp}}, StatusUp},
+		{"non critical down", []*stubChecker{{name: "a", critical: true, status: StatusUp}, {name: "b", status: StatusDown}}, StatusDegraded},
+		{"critical degraded", []*stubChecker{{name: "a", critical: true, status: StatusDegraded}}, StatusDegraded},
+		{"critical down", []*stubChecker{{name: "a", critical: true, status: StatusDown}, {name: "b", status: StatusUp}}, StatusDown},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry(time.Minute, time.Second)
+			for _, checker := range tt.checkers {
+				registry.Register(checker)
+			}
+			report := registry.Report(context.Background())
+			assert.Equal(t, tt.want, report.Status)
+			assert.Len(t, report.Checks, len(tt.checkers))
+			assert.Equal(t, tt.checkers[0].name, report.Checks[0].Name)
+		})
+	}
+}
+
+func Test_Report_CachesResults(t *testing.T) {
+	checker := &stubChecker{name: "a", status: StatusUp}
+	registry := NewRegistry(time.Minute, time.Second)
+	registry.Register(checker)
+	first := registry.Report(context.Background())
+	second := registry.Report(context.Background())
+	assert.Equal(t, int32(1), checker.calls.Load())
+	assert.Equal(t, first.Checks[0].CheckedAt, second.Checks[0].CheckedAt)
+
+	uncached := NewRegistry(0, time.Second)
+	uncached.Register(checker)
+	uncached.Report(context.Background())
+	uncached.Report(context.Background())
+	assert.Equal(t, int32(3), checker.calls.Load())
+}
+
+func Test_DatabaseChecker(t *testing.T) {
+	db := mocks.NewDBPool(t)
+	db.On("Ping", mock.Anything).Return(nil).Once()
+	db.On("Ping", mock.Anything).Return(errors.New("connection refused")).Once()
+	checker := NewDatabaseChecker(db)
+	assert.Equal(t, StatusUp, checker.Check(context.Background()).Status)
+	outcome := checker.Check(context.Background())
+	assert.Equal(t, StatusDown, outcome.Status)
+	assert.Contains(t, outcome.Message, "connection refused")
+}
+
+func Test_PoolChecker_WithoutStats(t *testing.T) {
+	outcome := NewPoolChecker(mocks.NewDBPool(t), 0.9).Check(context.Background())
+	assert.Equal(t, StatusUp, outcome.Status)
+}
@@ -0,0 +1,295 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ComponentField names the entry field that selects the per-component level, see ForComponent.
+const ComponentField = "component"
+
+// LevelSetting is one level in effect, ExpiresAt is set when it reverts on its own.
+type LevelSetting struct {
+	Level     string     `json:"level"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// LevelState is the global level and the component overrides in effect.
+type LevelState struct {
+	LevelSetting
+	Components map[string]LevelSetting `json:"components"`
+}
+
+// LevelControl changes log levels at runtime, globally or for one component, optionally for a
+// limited time only. The logger is set to the most verbose level in effect and a formatter filter
+// drops the entries that are below the level of their component.
+type LevelControl struct {
+	logger *logrus.Logger
+	mu     sync.Mutex
+	global level
+	// components maps lower-cased component names to their override
+	components map[string]level
+	// snapshot is read on every log line, it is replaced on each change and never modified
+	snapshot atomic.Pointer[levels]
+}
+
+type level struct {
+	value     logrus.Level
+	expiresAt time.Time
+	// previous is restored when the level expires, nil removes a component override
+	previous *level
+	timer    *time.Timer
+}
+
+type levels struct {
+	global     logrus.Level
+	components map[string]logrus.Level
+}
+
+var defaultLevels = NewLevelControl(logrus.StandardLogger())
+
+// DefaultLevels controls the standard logger every component logs through.
+func DefaultLevels() *LevelControl {
+	return defaultLevels
+}
+
+func NewLevelControl(logger *logrus.Logger) *LevelControl {
+	control := &LevelControl{logger: logger, global: level{value: logger.GetLevel()}, components: map[string]level{}}
+	control.apply()
+	return control
+}
+
+// Install sets the formatter and the configured level of the logger, it has to run before the
+// logger is used and resets any runtime change.
+func (l *LevelControl) Install(formatter logrus.Formatter, configured logrus.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stopTimers()
+	l.global = level{value: configured}
+	l.components = map[string]level{}
+	l.logger.SetFormatter(&levelFilter{Formatter: formatter, control: l})
+	l.apply()
+}
+
+// SetFormatter replaces the formatter of the logger, keeping the levels in effect.
+func (l *LevelControl) SetFormatter(formatter logrus.Formatter) {
+	l.logger.SetFormatter(&levelFilter{Formatter: formatter, control: l})
+}
+
+// SetLevel changes the level of component, or the global level when component is empty. With a
+// positive ttl the previous level is restored once it elapses.
+func (l *LevelControl) SetLevel(component string, value logrus.Level, ttl time.Duration) LevelState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	component = strings.ToLower(component)
+	current, exists := l.current(component)
+	setting := level{value: value}
+	if ttl > 0 {
+		// A temporary level replacing another one reverts to the level set before both
+		setting.previous = current.previous
+		if exists && current.expiresAt.IsZero() {
+			setting.previous = &level{value: current.value}
+		}
+		setting.expiresAt = time.Now().Add(ttl)
+		setting.timer = time.AfterFunc(ttl, func() { l.expire(component, setting.expiresAt) })
+	}
+	if current.timer != nil {
+		current.timer.Stop()
+	}
+	l.set(component, setting)
+	return l.state()
+}
+
+// ResetLevel removes the override of component, or restores the configured global level when
+// component is empty.
+func (l *LevelControl) ResetLevel(component string, configured logrus.Level) LevelState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	component = strings.ToLower(component)
+	if current, _ := l.current(component); current.timer != nil {
+		current.timer.Stop()
+	}
+	if component == "" {
+		l.global = level{value: configured}
+	} else {
+		delete(l.components, component)
+	}
+	l.apply()
+	return l.state()
+}
+
+func (l *LevelControl) State() LevelState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.state()
+}
+
+// Enabled tells whether an entry of the component at value is written.
+func (l *LevelControl) Enabled(component string, value logrus.Level) bool {
+	snapshot := l.snapshot.Load()
+	threshold, ok := snapshot.components[strings.ToLower(component)]
+	if !ok {
+		threshold = snapshot.global
+	}
+	return value <= threshold
+}
+
+// current returns the setting of component and whether one is set, components without an
+// override report the global level.
+func (l *LevelControl) current(component string) (level, bool) {
+	if component == "" {
+		return l.global, true
+	}
+	setting, ok := l.components[component]
+	if !ok {
+		return level{value: l.global.value}, false
+	}
+	return setting, true
+}
+
+func (l *LevelControl) set(component string, setting level) {
+	if component == "" {
+		l.global = setting
+	} else {
+		l.components[component] = setting
+	}
+	l.apply()
+}
+
+// expire restores the previous level, unless the level was changed again since.
+func (l *LevelControl) expire(component string, expiresAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	current, exists := l.current(component)
+	if !exists || !current.expiresAt.Equal(expiresAt) {
+		return
+	}
+	if current.previous == nil {
+		delete(l.components, component)
+		l.apply()
+	} else {
+		l.set(component, *current.previous)
+	}
+	name := component
+	if name == "" {
+		name = "global"
+	}
+	l.logger.Infof("Log level of %s reverted after its TTL", name)
+}
+
+func (l *LevelControl) stopTimers() {
+	if l.global.timer != nil {
+		l.global.timer.Stop()
+	}
+	for _, setting := range l.components {
+		if setting.timer != nil {
+			setting.timer.Stop()
+		}
+	}
+}
+
+// apply publishes a new snapshot and lowers the logger to the most verbose level in effect,
+// entries below it would otherwise never reach the filter.
+func (l *LevelControl) apply() {
+	snapshot := &levels{global: l.global.value, components: make(map[string]logrus.Level, len(l.components))}
+	verbosest := l.global.value
+	for component, setting := range l.components {
+		snapshot.components[component] = setting.value
+		if setting.value > verbosest {
+			verbosest = setting.value
+		}
+	}
+	l.snapshot.Store(snapshot)
+	l.logger.SetLevel(verbosest)
+}
+
+func (l *LevelControl) state() LevelState {
+	state := LevelState{LevelSetting: l.global.setting(), Components: make(map[string]LevelSetting, len(l.components))}
+	for component, setting := range l.components {
+		state.Components[component] = setting.setting()
+	}
+	return state
+}
+
+func (l level) setting() LevelSetting {
+	setting := LevelSetting{Level: l.value.String()}
+	if !l.expiresAt.IsZero() {
+		expiresAt := l.expiresAt.UTC()
+		setting.ExpiresAt = &expiresAt
+	}
+	return setting
+}
+
+// levelFilter drops the entries below the level of their component before they are formatted.
+type levelFilter struct {
+	logrus.Formatter
+	control *LevelControl
+}
+
+func (f *levelFilter) Format(entry *logrus.Entry) ([]byte, error) {
+	component, _ := entry.Data[ComponentField].(string)
+	if !f.control.Enabled(component, entry.Level) {
+		return nil, nil
+	}
+	return f.Formatter.Format(entry)
+}
+
+var (
+	componentsMu sync.Mutex
+	components   = map[string]bool{}
+)
+
+// RegisterComponent declares a component whose level can be set on its own and returns its name,
+// packages register theirs when they are initialised.
+func RegisterComponent(component string) string {
+	componentsMu.Lock()
+	defer componentsMu.Unlock()
+	components[strings.ToLower(component)] = true
+	return component
+}
+
+// IsComponent tells whether component was registered, whatever its case.
+func IsComponent(component string) bool {
+	componentsMu.Lock()
+	defer componentsMu.Unlock()
+	return components[strings.ToLower(component)]
+}
+
+// Components lists the registered components in alphabetical order.
+func Components() []string {
+	componentsMu.Lock()
+	defer componentsMu.Unlock()
+	names := make([]string, 0, len(components))
+	for component := range components {
+		names = append(names, component)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ForComponent returns the entry of the request tagged with component, whose level can then be
+// set on its own at /internal/log.
+func ForComponent(ctx context.Context, component string) *logrus.Entry {
+	return FromContext(ctx).WithField(ComponentField, component)
+}
+
+// Component returns a standard logger entry tagged with component, for code running without a context.
+func Component(component string) *logrus.Entry {
+	return logrus.WithField(ComponentField, component)
+}
+
+// ParseLevel accepts the logrus level names, e.g. "debug" or "warning".
+func ParseLevel(name string) (logrus.Level, error) {
+	value, err := logrus.ParseLevel(name)
+	if err != nil {
+		return value, fmt.Errorf("%q is not a log level, use panic, fatal, error, warn, info, debug or trace", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newControlledLogger(level logrus.Level) (*LevelControl, *bytes.Buffer) {
+	logger := logrus.New()
+	output := &bytes.Buffer{}
+	logger.SetOutput(output)
+	control := NewLevelControl(logger)
+	control.Install(&logrus.TextFormatter{DisableTimestamp: true}, level)
+	return control, output
+}
+
+func TestLevelControl_ComponentOverride(t *testing.T) {
+	control, output := newControlledLogger(logrus.InfoLevel)
+	control.SetLevel("Repository", logrus.DebugLevel, 0)
+
+	control.logger.WithField(ComponentField, "repository").Debug("repository detail")
+	control.logger.WithField(ComponentField, "services").Debug("services detail")
+	control.logger.Debug("untagged detail")
+	control.logger.WithField(ComponentField, "services").Info("services info")
+
+	assert.Contains(t, output.String(), "repository detail")
+	assert.NotContains(t, output.String(), "services detail")
+	assert.NotContains(t, output.String(), "untagged detail")
+	assert.Contains(t, output.String(), "services info")
+	assert.Equal(t, logrus.DebugLevel, control.logger.GetLevel(), "the logger lets the most verbose level through")
+	assert.Equal(t, "debug", control.State().Components["repository"].Level)
+}
+
+func TestLevelControl_QuieterComponent(t *testing.T) {
+	control, output := newControlledLogger(logrus.InfoLevel)
+	control.SetLevel("http", logrus.ErrorLevel, 0)
+
+	control.logger.WithField(ComponentField, "http").Warn("noisy warning")
+	control.logger.Warn("other warning")
+	assert.NotContains(t, output.String(), "noisy warning")
+	assert.Contains(t, output.String(), "other warning")
+}
+
+func TestLevelControl_TTLReverts(t *testing.T) {
+	control, _ := newControlledLogger(logrus.InfoLevel)
+	control.SetLevel("", logrus.WarnLevel, 0)
+	state := control.SetLevel("", logrus.DebugLevel, 20*time.Millisecond)
+	assert.Equal(t, "debug", state.Level)
+	assert.NotNil(t, state.ExpiresAt)
+	// A second temporary level reverts to the level set before both
+	control.SetLevel("", logrus.TraceLevel, 40*time.Millisecond)
+	control.SetLevel("repository", logrus.DebugLevel, 20*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		state := control.State()
+		return state.Level == "warning" && len(state.Components) == 0
+	}, time.Second, 5*time.Millisecond)
+	assert.Nil(t, control.State().ExpiresAt)
+	assert.Equal(t, logrus.WarnLevel, control.logger.GetLevel())
+}
+
+func TestLevelControl_ResetLevel(t *testing.T) {
+	control, _ := newControlledLogger(logrus.InfoLevel)
+	control.SetLevel("", logrus.DebugLevel, time.Hour)
+	control.SetLevel("services", logrus.TraceLevel, 0)
+
+	control.ResetLevel("SERVICES", logrus.InfoLevel)
+	assert.Empty(t, control.State().Components)
+	state := control.ResetLevel("", logrus.InfoLevel)
+	assert.Equal(t, "info", state.Level)
+	assert.Nil(t, state.ExpiresAt)
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, name := range []string{"panic", "fatal", "error", "warn", "warning", "info", "debug", "trace"} {
+		_, err := ParseLevel(name)
+		assert.NoError(t, err, name)
+	}
+	_, err := ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestRegisterComponent(t *testing.T) {
+	assert.Equal(t, "Billing", RegisterComponent("Billing"))
+	assert.True(t, IsComponent("billing"))
+	assert.True(t, IsComponent("BILLING"))
+	assert.False(t, IsComponent("biling"))
+	assert.Contains(t, Components(), "billing")
+}
@@ -0,0 +1,36 @@
+// Package logging carries a request-scoped logrus entry through the context.Context, so that what
+// services and repositories log can be tied to the request, and the user, it was logged for.
+package logging
+
+import (
+	"context"
+	"starter/internal/app/correlation"
+
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey struct{}
+
+// WithEntry stores the entry every log line of the request should go through.
+func WithEntry(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, entry)
+}
+
+// WithFields adds fields to the entry of ctx, e.g. the user once the request is authenticated.
+func WithFields(ctx context.Context, fields logrus.Fields) context.Context {
+	return WithEntry(ctx, FromContext(ctx).WithFields(fields))
+}
+
+// FromContext returns the entry of the request. Outside of requests, in jobs and commands, it falls
+// back to the standard logger, with the request ID of ctx when there is one. Entries share the
+// standard logger, so the level set at runtime applies to them all.
+func FromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return entry.WithContext(ctx)
+	}
+	entry := logrus.NewEntry(logrus.StandardLogger()).WithContext(ctx)
+	if requestID := correlation.RequestID(ctx); requestID != "" {
+		entry = entry.WithField("requestId", requestID)
+	}
+	return entry
+}
@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"context"
+	"starter/internal/app/correlation"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext_ReturnsRequestEntry(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	ctx := WithEntry(context.Background(), logger.WithField("requestId", "req-1"))
+	ctx = WithFields(ctx, logrus.Fields{"userId": int64(7)})
+
+	FromContext(ctx).Info("hello")
+	entry := hook.LastEntry()
+	assert.Equal(t, "req-1", entry.Data["requestId"])
+	assert.Equal(t, int64(7), entry.Data["userId"])
+	assert.Equal(t, ctx, entry.Context)
+}
+
+func TestFromContext_FallsBackToStandardLogger(t *testing.T) {
+	entry := FromContext(correlation.WithRequestID(context.Background(), "job-1"))
+	assert.Equal(t, logrus.StandardLogger(), entry.Logger)
+	assert.Equal(t, "job-1", entry.Data["requestId"])
+
+	assert.NotContains(t, FromContext(context.Background()).Data, "requestId")
+}
@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Redacted replaces the values that must never reach the logs.
+const Redacted = "[REDACTED]"
+
+// sensitiveKey matches field names and key=value pairs holding credentials, such as password,
+// userPassword, access_token, apiKey or Authorization.
+const sensitiveKey = `(?:password|passwd|secret|token|api_?key|authorization|cookie)`
+
+var (
+	sensitiveField = regexp.MustCompile(`(?i)` + sensitiveKey)
+	// A key, its separator as in key=value, key: value or "key":"value", then the value
+	sensitivePair = regexp.MustCompile(`(?i)(\b[A-Za-z_-]*` + sensitiveKey + `[A-Za-z_-]*["']?\s*[:=]\s*["']?)([^"'\s&,;}]+)`)
+	bearerToken   = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`)
+	jwt           = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	email         = regexp.MustCompile(`\b([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)+)\b`)
+)
+
+// RedactionHook masks emails, tokens, passwords, Authorization headers and the configured query
+// parameters in the message and fields of every entry. Hooks run before entries are formatted,
+// so no sink ever receives the original values.
+type RedactionHook struct {
+	queryParam *regexp.Regexp
+}
+
+// NewRedactionHook also masks the values of the query parameters named in params, e.g. "code".
+func NewRedactionHook(params []string) *RedactionHook {
+	hook := &RedactionHook{}
+	if len(params) > 0 {
+		quoted := make([]string, len(params))
+		for i, param := range params {
+			quoted[i] = regexp.QuoteMeta(param)
+		}
+		hook.queryParam = regexp.MustCompile(`(?i)([?&](?:` + strings.Join(quoted, "|") + `)=)[^&\s"'#]*`)
+	}
+	return hook
+}
+
+func (h *RedactionHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire rewrites the entry in place, logrus hands hooks a copy of the entry and of its fields.
+func (h *RedactionHook) Fire(entry *logrus.Entry) error {
+	entry.Message = h.Redact(entry.Message)
+	for key, value := range entry.Data {
+		if sensitiveField.MatchString(key) {
+			entry.Data[key] = Redacted
+			continue
+		}
+		switch typed := value.(type) {
+		case string:
+			entry.Data[key] = h.Redact(typed)
+		case []byte:
+			entry.Data[key] = h.Redact(string(typed))
+		case error:
+			entry.Data[key] = h.Redact(typed.Error())
+		case fmt.Stringer:
+			entry.Data[key] = h.Redact(typed.String())
+		}
+	}
+	return nil
+}
+
+// Redact masks the sensitive values found in text. Emails keep their first character and their
+// domain, e.g. j***@example.com, so that log lines can still be told apart.
+func (h *RedactionHook) Redact(text string) string {
+	if h.queryParam != nil {
+		text = h.queryParam.ReplaceAllString(text, "${1}"+Redacted)
+	}
+	text = jwt.ReplaceAllString(text, Redacted)
+	text = bearerToken.ReplaceAllString(text, "${1} "+Redacted)
+	text = sensitivePair.ReplaceAllStringFunc(text, func(pair string) string {
+		match := sensitivePair.FindStringSubmatch(pair)
+		if match[2] == Redacted || strings.EqualFold(match[2], "bearer") || strings.EqualFold(match[2], "basic") {
+			return pair
+		}
+		return match[1] + Redacted
+	})
+	return email.ReplaceAllString(text, "${1}***@${2}")
+}
@@ -0,0 +1,222 @@
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat names rotated files, it sorts in time order.
+const backupTimeFormat = "20060102T150405.000"
+
+// RotateOptions bounds a RotatingFile, zero values disable the matching limit.
+type RotateOptions struct {
+	Mode       os.FileMode
+	MaxSize    int64
+	Every      time.Duration
+	MaxBackups int
+	Compress   bool
+}
+
+// RotatingFile is an append-only log file moved aside to <name>-<time><ext> once it outgrows
+// MaxSize or crosses an Every boundary, UTC midnight for 24h. Rotated files are gzipped and pruned
+// in the background, Close waits for them.
+type RotatingFile struct {
+	path    string
+	options RotateOptions
+	now     func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	// background tracks the compression and pruning of rotated files, archiving runs them one at a time
+	background sync.WaitGroup
+	archiving  sync.Mutex
+}
+
+func OpenRotatingFile(path string, options RotateOptions) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, options: options, now: time.Now}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, err
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open appends to the current file, a file left by a previous run counts from its last write.
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, r.options.Mode)
+	if err != nil {
+		return err
+	}
+	// The mode only applies on creation, a file left by an earlier run may be more permissive
+	if err := file.Chmod(r.options.Mode); err != nil {
+		file.Close()
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file, r.size, r.openedAt = file, info.Size(), r.now()
+	if info.Size() > 0 {
+		r.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.shouldRotate(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			// Keep logging to the current file rather than losing the line
+			fmt.Fprintf(os.Stderr, "Failed to rotate %s: %v\n", r.path, err)
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) shouldRotate(incoming int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.options.MaxSize > 0 && r.size+incoming > r.options.MaxSize {
+		return true
+	}
+	if every := r.options.Every; every > 0 {
+		return !r.now().UTC().Truncate(every).Equal(r.openedAt.UTC().Truncate(every))
+	}
+	return false
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	extension := filepath.Ext(r.path)
+	backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(r.path, extension), r.now().UTC().Format(backupTimeFormat), extension)
+	renameErr := os.Rename(r.path, backup)
+	// Reopen even when the rename failed, the file is closed by now
+	if err := r.open(); err != nil {
+		return errors.Join(renameErr, err)
+	}
+	if renameErr != nil {
+		return renameErr
+	}
+	r.background.Add(1)
+	go func() {
+		defer r.background.Done()
+		r.archive(backup)
+	}()
+	return nil
+}
+
+// archive compresses a rotated file and removes the backups beyond MaxBackups. Errors are
+// reported on stderr, logging them would go through this very file.
+func (r *RotatingFile) archive(backup string) {
+	r.archiving.Lock()
+	defer r.archiving.Unlock()
+	if r.options.Compress {
+		// A quick succession of rotations may have pruned the backup already
+		if err := compress(backup, r.options.Mode); err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Failed to compress %s: %v\n", backup, err)
+		}
+	}
+	if r.options.MaxBackups <= 0 {
+		return
+	}
+	backups, err := r.backups()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list the backups of %s: %v\n", r.path, err)
+		return
+	}
+	for len(backups) > r.options.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Failed to remove %s: %v\n", backups[0], err)
+		}
+		backups = backups[1:]
+	}
+}
+
+// backups lists the rotated files, oldest first.
+func (r *RotatingFile) backups() ([]string, error) {
+	extension := filepath.Ext(r.path)
+	prefix := filepath.Base(strings.TrimSuffix(r.path, extension)) + "-"
+	entries, err := os.ReadDir(filepath.Dir(r.path))
+	if err != nil {
+		return nil, err
+	}
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		stamp, found := strings.CutPrefix(name, prefix)
+		if !found {
+			continue
+		}
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), extension)
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			backups = append(backups, filepath.Join(filepath.Dir(r.path), name))
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+func compress(path string, mode os.FileMode) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	target, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(target)
+	_, copyErr := io.Copy(writer, source)
+	if err := errors.Join(copyErr, writer.Close(), target.Close()); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Sync flushes the current file to disk.
+func (r *RotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Sync()
+}
+
+// Close flushes and closes the current file, then waits for the rotated files to be archived.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	var err error
+	if r.file != nil {
+		err = errors.Join(r.file.Sync(), r.file.Close())
+		r.file = nil
+	}
+	r.mu.Unlock()
+	r.background.Wait()
+	return err
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile_RotatesBySizeAndPrunes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs", "app.log")
+	file, err := OpenRotatingFile(path, RotateOptions{Mode: 0o600, MaxSize: 10, MaxBackups: 2, Compress: true})
+	require.NoError(t, err)
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	file.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+
+	for _, line := range []string{"first---\n", "second--\n", "third---\n", "fourth--\n"} {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, file.Close())
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fourth--\n", string(current))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	backups, err := filepath.Glob(filepath.Join(dir, "logs", "app-*.log.gz"))
+	require.NoError(t, err)
+	require.Len(t, backups, 2, "the oldest backup is pruned")
+	assert.Equal(t, "second--\n", gunzip(t, backups[0]))
+	assert.Equal(t, "third---\n", gunzip(t, backups[1]))
+}
+
+func TestRotatingFile_RotatesOnTimeBoundary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := OpenRotatingFile(path, RotateOptions{Mode: 0o640, Every: 24 * time.Hour})
+	require.NoError(t, err)
+	clock := time.Date(2026, 1, 2, 23, 59, 0, 0, time.UTC)
+	file.now = func() time.Time { return clock }
+	file.openedAt = clock
+
+	_, _ = file.Write([]byte("before midnight\n"))
+	clock = clock.Add(30 * time.Second)
+	_, _ = file.Write([]byte("same day\n"))
+	clock = clock.Add(time.Minute)
+	_, _ = file.Write([]byte("next day\n"))
+	require.NoError(t, file.Close())
+
+	backups, err := filepath.Glob(strings.TrimSuffix(path, ".log") + "-*.log")
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	rotated, _ := os.ReadFile(backups[0])
+	assert.Equal(t, "before midnight\nsame day\n", string(rotated))
+	current, _ := os.ReadFile(path)
+	assert.Equal(t, "next day\n", string(current))
+}
+
+func gunzip(t *testing.T, path string) string {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestRotatingFile_TightensTheModeOfAnExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("left by the baseline\n"), 0o600))
+	require.NoError(t, os.Chmod(path, 0o666))
+
+	file, err := OpenRotatingFile(path, RotateOptions{Mode: 0o640})
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+}
@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"starter/internal/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Sink is one destination of the logs, with a format of its own.
+type Sink struct {
+	Name      string
+	Formatter logrus.Formatter
+	Writer    io.Writer
+}
+
+// Sinks are the destinations chosen by LOG_SINKS. Used as the formatter of a logger, they format
+// each entry once per sink and write it there themselves, the logger's own output receives nothing.
+// logrus formats under its lock, so sinks are written one entry at a time.
+type Sinks struct {
+	sinks []Sink
+	files []*RotatingFile
+}
+
+// OpenSinks opens the sinks of the configuration, the file sink creates its directory if needed.
+func OpenSinks(logConfig config.LogConfig) (*Sinks, error) {
+	sinks := &Sinks{}
+	for _, name := range logConfig.SinkList() {
+		switch name {
+		case "stdout":
+			sinks.sinks = append(sinks.sinks, Sink{Name: name, Formatter: &logrus.JSONFormatter{}, Writer: os.Stdout})
+		case "text":
+			sinks.sinks = append(sinks.sinks, Sink{Name: name, Formatter: &logrus.TextFormatter{FullTimestamp: true}, Writer: os.Stdout})
+		case "file":
+			mode, err := logConfig.Mode()
+			if err != nil {
+				return nil, errors.Join(fmt.Errorf("LOG_FILE_MODE: %w", err), sinks.Close())
+			}
+			file, err := OpenRotatingFile(logConfig.File, RotateOptions{
+				Mode:       mode,
+				MaxSize:    int64(logConfig.MaxSizeMB) << 20,
+				Every:      logConfig.RotateEvery,
+				MaxBackups: logConfig.MaxBackups,
+				Compress:   logConfig.Compress,
+			})
+			if err != nil {
+				return nil, errors.Join(fmt.Errorf("LOG_FILE: %w", err), sinks.Close())
+			}
+			sinks.files = append(sinks.files, file)
+			sinks.sinks = append(sinks.sinks, Sink{Name: name, Formatter: &logrus.JSONFormatter{}, Writer: file})
+		default:
+			return nil, errors.Join(fmt.Errorf("LOG_SINKS: unknown sink %q", name), sinks.Close())
+		}
+	}
+	return sinks, nil
+}
+
+func NewSinks(sinks ...Sink) *Sinks {
+	return &Sinks{sinks: sinks}
+}
+
+func (s *Sinks) Format(entry *logrus.Entry) ([]byte, error) {
+	var errs []error
+	for _, sink := range s.sinks {
+		// Formatters write into the pooled buffer of the entry when there is one
+		if entry.Buffer != nil {
+			entry.Buffer.Reset()
+		}
+		serialized, err := sink.Formatter.Format(entry)
+		if err == nil {
+			_, err = sink.Writer.Write(serialized)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s sink: %w", sink.Name, err))
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Console returns the sinks writing to stdout, the standard JSON sink when there are none, to
+// keep logging once the files are closed.
+func (s *Sinks) Console() *Sinks {
+	console := &Sinks{}
+	for _, sink := range s.sinks {
+		if sink.Writer == os.Stdout {
+			console.sinks = append(console.sinks, sink)
+		}
+	}
+	if len(console.sinks) == 0 {
+		console.sinks = append(console.sinks, Sink{Name: "stdout", Formatter: &logrus.JSONFormatter{}, Writer: os.Stdout})
+	}
+	return console
+}
+
+// Close closes the file sinks, waiting for the rotated files to be compressed.
+func (s *Sinks) Close() error {
+	var errs []error
+	for _, file := range s.files {
+		errs = append(errs, file.Close())
+	}
+	s.files = nil
+	return errors.Join(errs...)
+}
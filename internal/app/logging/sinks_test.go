@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"starter/internal/config"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinks_WriteEachFormat(t *testing.T) {
+	jsonOutput, textOutput := &bytes.Buffer{}, &bytes.Buffer{}
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	logger.SetFormatter(NewSinks(
+		Sink{Name: "json", Formatter: &logrus.JSONFormatter{}, Writer: jsonOutput},
+		Sink{Name: "text", Formatter: &logrus.TextFormatter{DisableTimestamp: true}, Writer: textOutput},
+	))
+	logger.WithField("requestId", "r-1").Info("hello")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(jsonOutput.Bytes(), &entry))
+	assert.Equal(t, "hello", entry["msg"])
+	assert.Equal(t, "r-1", entry["requestId"])
+	assert.Equal(t, "level=info msg=hello requestId=r-1\n", textOutput.String())
+}
+
+func TestOpenSinks(t *testing.T) {
+	logConfig := config.Defaults().Log
+	logConfig.Sinks = "text,file"
+	logConfig.File = filepath.Join(t.TempDir(), "app.log")
+	sinks, err := OpenSinks(logConfig)
+	require.NoError(t, err)
+	assert.Len(t, sinks.sinks, 2)
+	assert.Len(t, sinks.Console().sinks, 1)
+	assert.NoError(t, sinks.Close())
+
+	logConfig.FileMode = "rw-r--r--"
+	_, err = OpenSinks(logConfig)
+	assert.ErrorContains(t, err, "LOG_FILE_MODE")
+}
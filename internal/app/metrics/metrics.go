@@ -0,0 +1,96 @@
+// Package metrics holds the Prometheus collectors of the application, exposed with the Go and
+// process collectors at /internal/metrics. They follow RED: rate, errors and duration.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests served, by route template, method and status code.",
+	}, []string{"route", "method", "status"})
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Time taken to serve HTTP requests, by route template, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	dbQueries = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Time taken by database statements, by object type and operation.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"object_type", "operation"})
+	dbErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Database statements that failed, by object type and operation. Lookups finding nothing are not counted.",
+	}, []string{"object_type", "operation"})
+
+	partnerCalls = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "partner_call_duration_seconds",
+		Help:    "Time taken by partner calls, retries included, by outcome.",
+		Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"outcome"})
+	partnerRetries = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "partner_call_retries_total",
+		Help: "Partner call attempts retried after a transport error.",
+	})
+	partnerFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "partner_call_failures_total",
+		Help: "Partner calls that failed once retries were exhausted or on an error response.",
+	})
+)
+
+// UnmatchedRoute labels requests that matched no route, so that scanners cannot blow up the
+// label cardinality with random paths.
+const UnmatchedRoute = "unmatched"
+
+// OtherMethod labels requests with a non-standard method, any token is a valid method.
+const OtherMethod = "other"
+
+var standardMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true, http.MethodPatch: true,
+	http.MethodDelete: true, http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// ObserveRequest records a served request. route is the template, e.g. /user/:id, never the raw path.
+func ObserveRequest(route string, method string, status int, elapsed time.Duration) {
+	if route == "" {
+		route = UnmatchedRoute
+	}
+	if !standardMethods[method] {
+		method = OtherMethod
+	}
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(route, method, code).Inc()
+	httpDuration.WithLabelValues(route, method, code).Observe(elapsed.Seconds())
+}
+
+// ObserveQuery records a database statement, failed tells whether it has to count as an error.
+func ObserveQuery(objectType string, operation string, elapsed time.Duration, failed bool) {
+	dbQueries.WithLabelValues(objectType, operation).Observe(elapsed.Seconds())
+	if failed {
+		dbErrors.WithLabelValues(objectType, operation).Inc()
+	}
+}
+
+// PartnerRetry counts one retried partner attempt.
+func PartnerRetry() {
+	partnerRetries.Inc()
+}
+
+// ObservePartnerCall records a partner call once its retries are over.
+func ObservePartnerCall(elapsed time.Duration, failed bool) {
+	outcome := "success"
+	if failed {
+		outcome = "failure"
+		partnerFailures.Inc()
+	}
+	partnerCalls.WithLabelValues(outcome).Observe(elapsed.Seconds())
+}
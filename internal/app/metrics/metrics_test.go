@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveRequest_LabelsUnmatchedRoutes(t *testing.T) {
+	before := testutil.ToFloat64(httpRequests.WithLabelValues(UnmatchedRoute, http.MethodGet, "404"))
+	ObserveRequest("", http.MethodGet, http.StatusNotFound, time.Millisecond)
+	assert.Equal(t, before+1, testutil.ToFloat64(httpRequests.WithLabelValues(UnmatchedRoute, http.MethodGet, "404")))
+}
+
+func TestObserveRequest_LabelsNonStandardMethods(t *testing.T) {
+	before := testutil.ToFloat64(httpRequests.WithLabelValues(UnmatchedRoute, OtherMethod, "404"))
+	ObserveRequest("", "PROPFIND", http.StatusNotFound, time.Millisecond)
+	ObserveRequest("", "X-RANDOM-1", http.StatusNotFound, time.Millisecond)
+	assert.Equal(t, before+2, testutil.ToFloat64(httpRequests.WithLabelValues(UnmatchedRoute, OtherMethod, "404")))
+}
+
+func TestObserveQuery_CountsFailures(t *testing.T) {
+	before := testutil.ToFloat64(dbErrors.WithLabelValues("user", "SELECT"))
+	ObserveQuery("user", "SELECT", time.Millisecond, false)
+	ObserveQuery("user", "SELECT", time.Millisecond, true)
+	assert.Equal(t, before+1, testutil.ToFloat64(dbErrors.WithLabelValues("user", "SELECT")))
+}
+
+func TestObservePartnerCall(t *testing.T) {
+	failures := testutil.ToFloat64(partnerFailures)
+	retries := testutil.ToFloat64(partnerRetries)
+	PartnerRetry()
+	ObservePartnerCall(time.Second, true)
+	ObservePartnerCall(time.Second, false)
+	assert.Equal(t, failures+1, testutil.ToFloat64(partnerFailures))
+	assert.Equal(t, retries+1, testutil.ToFloat64(partnerRetries))
+	assert.Equal(t, 2, testutil.CollectAndCount(partnerCalls), "one series per outcome")
+}
+
+func TestRegisterPool_SkipsPoolsWithoutStatistics(t *testing.T) {
+	assert.NoError(t, RegisterPool(nil))
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"starter/internal/config"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolStater is implemented by *pgxpool.Pool but not by the DBPool mocks.
+type poolStater interface {
+	Stat() *pgxpool.Stat
+}
+
+var (
+	poolAcquired = prometheus.NewDesc("db_pool_acquired_connections", "Connections currently in use.", nil, nil)
+	poolIdle     = prometheus.NewDesc("db_pool_idle_connections", "Connections currently idle.", nil, nil)
+	poolTotal    = prometheus.NewDesc("db_pool_total_connections", "Connections currently open, constructing ones included.", nil, nil)
+	poolMax      = prometheus.NewDesc("db_pool_max_connections", "Maximum size of the pool, DB_MAX_POOL_CONNECTIONS.", nil, nil)
+	poolAcquires = prometheus.NewDesc("db_pool_acquires_total", "Connections acquired from the pool.", nil, nil)
+	poolWaits    = prometheus.NewDesc("db_pool_empty_acquires_total", "Acquires that had to wait for a connection.", nil, nil)
+	poolWaited   = prometheus.NewDesc("db_pool_acquire_duration_seconds_total", "Time spent acquiring connections.", nil, nil)
+	poolCanceled = prometheus.NewDesc("db_pool_canceled_acquires_total", "Acquires cancelled by their context.", nil, nil)
+)
+
+// poolCollector reads the pool statistics on every scrape, so they are never stale.
+type poolCollector struct {
+	pool poolStater
+}
+
+// RegisterPool exposes the statistics of the connection pool. Pools without statistics, such as
+// the mocks, are skipped.
+func RegisterPool(db config.DBPool) error {
+	pool, ok := db.(poolStater)
+	if !ok {
+		return nil
+	}
+	return prometheus.Register(&poolCollector{pool: pool})
+}
+
+func (p *poolCollector) Describe(descs chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{poolAcquired, poolIdle, poolTotal, poolMax, poolAcquires, poolWaits, poolWaited, poolCanceled} {
+		descs <- desc
+	}
+}
+
+func (p *poolCollector) Collect(metrics chan<- prometheus.Metric) {
+	stat := p.pool.Stat()
+	metrics <- prometheus.MustNewConstMetric(poolAcquired, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	metrics <- prometheus.MustNewConstMetric(poolIdle, prometheus.GaugeValue, float64(stat.IdleConns()))
+	metrics <- prometheus.MustNewConstMetric(poolTotal, prometheus.GaugeValue, float64(stat.TotalConns()))
+	metrics <- prometheus.MustNewConstMetric(poolMax, prometheus.GaugeValue, float64(stat.MaxConns()))
+	metrics <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	metrics <- prometheus.MustNewConstMetric(poolWaits, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	metrics <- prometheus.MustNewConstMetric(poolWaited, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	metrics <- prometheus.MustNewConstMetric(poolCanceled, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
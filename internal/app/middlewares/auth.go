@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"net/http"
+	"starter/internal/app/constants"
+	"starter/internal/app/logging"
+	"starter/internal/app/models"
+	"starter/internal/app/services"
+	"starter/internal/app/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	AUTH_USER     = "AuthUser"
+	AUTH_HEADER   = "Authorization"
+	BEARER_PREFIX = "Bearer "
+)
+
+// AuthMiddleware validates the bearer token and stores the authenticated user on the context,
+// its ID is added to the request's log entry.
+func AuthMiddleware(authService services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(AUTH_HEADER)
+		if len(header) == 0 {
+			utils.ErrorResponse(c, http.StatusUnauthorized, constants.MISSING_AUTH_TOKEN)
+			return
+		}
+		if len(header) <= len(BEARER_PREFIX) || !strings.EqualFold(header[:len(BEARER_PREFIX)], BEARER_PREFIX) {
+			utils.ErrorResponse(c, http.StatusUnauthorized, constants.PARSE_TOKEN_FAIL)
+			return
+		}
+		user, err := authService.ValidateToken(c.Request.Context(), strings.TrimSpace(header[len(BEARER_PREFIX):]))
+		if err != nil {
+			utils.ErrorResponse(c, err.StatusCode, err.Message)
+			return
+		}
+		c.Set(AUTH_USER, user)
+		c.Request = c.Request.WithContext(logging.WithFields(c.Request.Context(), logrus.Fields{"userId": user.ID}))
+		c.Next()
+	}
+}
+
+// GetAuthUser returns the user placed on the context by AuthMiddleware.
+func GetAuthUser(c *gin.Context) (*models.User, bool) {
+	value, exists := c.Get(AUTH_USER)
+	if !exists {
+		return nil, false
+	}
+	user, ok := value.(*models.User)
+	return user, ok
+}
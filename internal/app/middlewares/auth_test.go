@@ -0,0 +1,78 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"starter/internal/app/constants"
+	"starter/internal/app/models"
+	"starter/internal/app/services/mocks"
+	"starter/internal/app/utils"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newAuthRouter answers 200 with the ID of the authenticated user once AuthMiddleware lets a request through.
+func newAuthRouter(authService *mocks.AuthService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", AuthMiddleware(authService), func(c *gin.Context) {
+		user, _ := GetAuthUser(c)
+		c.JSON(http.StatusOK, gin.H{"id": user.ID})
+	})
+	return router
+}
+
+func TestAuthMiddleware_RejectsMissingOrMalformedHeaders(t *testing.T) {
+	router := newAuthRouter(mocks.NewAuthService(t))
+	cases := []struct {
+		header  string
+		message string
+	}{
+		{"", constants.MISSING_AUTH_TOKEN},
+		{"Bearer", constants.PARSE_TOKEN_FAIL},
+		{"Bearer ", constants.PARSE_TOKEN_FAIL},
+		{"Basic dXNlcjpwYXNz", constants.PARSE_TOKEN_FAIL},
+		{"token-without-scheme", constants.PARSE_TOKEN_FAIL},
+	}
+	for _, tt := range cases {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.header != "" {
+			request.Header.Set(AUTH_HEADER, tt.header)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, tt.header)
+		assert.Contains(t, recorder.Body.String(), tt.message, tt.header)
+	}
+}
+
+func TestAuthMiddleware_PassesTheRejectionOfTheToken(t *testing.T) {
+	authService := mocks.NewAuthService(t)
+	authService.On("ValidateToken", mock.Anything, "revoked").
+		Return(nil, &utils.ErrorMessage{StatusCode: http.StatusUnauthorized, Message: constants.INVALID_SESSION})
+	router := newAuthRouter(authService)
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(AUTH_HEADER, "Bearer revoked")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), constants.INVALID_SESSION)
+}
+
+func TestAuthMiddleware_StoresTheUser(t *testing.T) {
+	authService := mocks.NewAuthService(t)
+	authService.On("ValidateToken", mock.Anything, "valid").Return(&models.User{ID: 42}, nil)
+	router := newAuthRouter(authService)
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	// The scheme is case-insensitive
+	request.Header.Set(AUTH_HEADER, "bearer valid")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"id": 42}`, recorder.Body.String())
+}
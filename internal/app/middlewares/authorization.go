@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"net/http"
+	"starter/internal/app/constants"
+	"starter/internal/app/models"
+	"starter/internal/app/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole only lets through authenticated users holding one of the given roles.
+// It must be chained after AuthMiddleware.
+func RequireRole(roles ...models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := GetAuthUser(c)
+		if !ok {
+			utils.ErrorResponse(c, http.StatusUnauthorized, constants.MISSING_AUTH_TOKEN)
+			return
+		}
+		for _, role := range roles {
+			if user.UserRole == role {
+				c.Next()
+				return
+			}
+		}
+		utils.ErrorResponse(c, http.StatusForbidden, constants.FORBIDDEN_ROLE)
+	}
+}
+
+// RequirePermission only lets through authenticated users whose role grants every given permission.
+// It must be chained after AuthMiddleware.
+func RequirePermission(permissions ...models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := GetAuthUser(c)
+		if !ok {
+			utils.ErrorResponse(c, http.StatusUnauthorized, constants.MISSING_AUTH_TOKEN)
+			return
+		}
+		for _, permission := range permissions {
+			if !user.UserRole.HasPermission(permission) {
+				utils.ErrorResponse(c, http.StatusForbidden, constants.UNAUTHORIZED)
+				return
+			}
+		}
+		c.Next()
+	}
+}
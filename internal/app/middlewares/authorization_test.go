@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"starter/internal/app/constants"
+	"starter/internal/app/models"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// authorize runs guard for a user of role, no user at all when role is empty.
+func authorize(role models.Role, guard gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) {
+		if role != "" {
+			c.Set(AUTH_USER, &models.User{ID: 1, UserRole: role})
+		}
+	}, guard, func(c *gin.Context) { c.Status(http.StatusOK) })
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	return recorder
+}
+
+func TestRequireRole(t *testing.T) {
+	guard := RequireRole(models.RoleAdmin, models.RoleTester)
+	assert.Equal(t, http.StatusOK, authorize(models.RoleAdmin, guard).Code)
+	assert.Equal(t, http.StatusOK, authorize(models.RoleTester, guard).Code)
+
+	denied := authorize(models.RoleViewer, guard)
+	assert.Equal(t, http.StatusForbidden, denied.Code)
+	assert.Contains(t, denied.Body.String(), constants.FORBIDDEN_ROLE)
+
+	anonymous := authorize("", guard)
+	assert.Equal(t, http.StatusUnauthorized, anonymous.Code)
+	assert.Contains(t, anonymous.Body.String(), constants.MISSING_AUTH_TOKEN)
+}
+
+func TestRequirePermission(t *testing.T) {
+	viewLogs := RequirePermission(models.PermissionViewLogs)
+	assert.Equal(t, http.StatusOK, authorize(models.RoleTester, viewLogs).Code)
+	assert.Equal(t, http.StatusForbidden, authorize(models.RoleViewer, viewLogs).Code)
+
+	manageLogs := RequirePermission(models.PermissionViewLogs, models.PermissionManageLogs)
+	assert.Equal(t, http.StatusOK, authorize(models.RoleAdmin, manageLogs).Code)
+	denied := authorize(models.RoleTester, manageLogs)
+	assert.Equal(t, http.StatusForbidden, denied.Code, "every permission is required")
+	assert.Contains(t, denied.Body.String(), constants.UNAUTHORIZED)
+
+	assert.Equal(t, http.StatusForbidden, authorize(models.Role("root"), viewLogs).Code, "unknown roles grant nothing")
+	assert.Equal(t, http.StatusUnauthorized, authorize("", viewLogs).Code)
+}
@@ -1,25 +1,35 @@
 package middlewares
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"net/http"
 	"os"
-	"starter/internal/app/utils"
+	"starter/internal/app/correlation"
+	"starter/internal/app/logging"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/timeout"
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid/v5"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// logComponent tags the request logs and what handlers log, its level can be set on its own at /internal/log
+var logComponent = logging.RegisterComponent("http")
+
 const (
 	REQUEST_ID     = "RequestID"
-	REQUEST_HEADER = "X-Request-ID"
+	REQUEST_HEADER = correlation.RequestIDHeader
+	TRACE_ID       = "TraceID"
 )
 
-// LoggerMiddleware returns a Gin middleware that logs HTTP requests.
+// LoggerMiddleware returns a Gin middleware that logs HTTP requests. It also stores on the request
+// context the entry carrying the request ID, trace ID and route, see logging.FromContext, so it has
+// to run after RequestIDMiddleware.
 func LoggerMiddleware(logger logrus.FieldLogger, notLogged ...string) gin.HandlerFunc {
 	// Get the hostname, or set it as "unknown" if an error occurs.
 	hostname, err := os.Hostname()
@@ -40,6 +50,15 @@ func LoggerMiddleware(logger logrus.FieldLogger, notLogged ...string) gin.Handle
 		// Save the original path since it might be modified by other handlers.
 		path := c.Request.URL.Path
 		start := time.Now()
+		reqId, _ := c.Get(REQUEST_ID)
+		traceId := c.GetString(TRACE_ID)
+		requestEntry := logger.WithFields(logrus.Fields{
+			"requestId":            reqId,
+			"traceId":              traceId,
+			"route":                c.FullPath(),
+			logging.ComponentField: logComponent,
+		})
+		c.Request = c.Request.WithContext(logging.WithEntry(c.Request.Context(), requestEntry))
 		c.Next()
 		stop := time.Since(start)
 		latency := int(math.Ceil(float64(stop.Nanoseconds()) / 1000000.0))
@@ -48,7 +67,6 @@ func LoggerMiddleware(logger logrus.FieldLogger, notLogged ...string) gin.Handle
 		clientUserAgent := c.Request.UserAgent()
 		referer := c.Request.Referer()
 		dataLength := c.Writer.Size()
-		reqId, _ := c.Get(REQUEST_ID)
 		if dataLength < 0 {
 			dataLength = 0
 		}
@@ -69,7 +87,6 @@ func LoggerMiddleware(logger logrus.FieldLogger, notLogged ...string) gin.Handle
 			"referer":    referer,
 			"dataLength": dataLength,
 			"userAgent":  clientUserAgent,
-			"requestId":  reqId,
 		}
 
 		// Log based on status code severity.
@@ -95,43 +112,87 @@ func LoggerMiddleware(logger logrus.FieldLogger, notLogged ...string) gin.Handle
 			clientUserAgent,
 			latency)
 
-		// Log the message with appropriate log level.
+		// Log the message with appropriate log level, through the entry of the request so that
+		// fields added by later middlewares, such as the user ID, are included.
+		requestLogger := logging.FromContext(c.Request.Context())
 		switch logLevel {
 		case logrus.ErrorLevel:
-			logger.WithFields(entryFields).Error(logMessage)
+			requestLogger.WithFields(entryFields).Error(logMessage)
 		case logrus.WarnLevel:
-			logger.WithFields(entryFields).Warn(logMessage)
+			requestLogger.WithFields(entryFields).Warn(logMessage)
 		case logrus.DebugLevel:
-			logger.WithFields(entryFields).Debug(logMessage)
+			requestLogger.WithFields(entryFields).Debug(logMessage)
 		case logrus.TraceLevel:
-			logger.WithFields(entryFields).Trace(logMessage)
+			requestLogger.WithFields(entryFields).Trace(logMessage)
 		case logrus.PanicLevel:
-			logger.WithFields(entryFields).Panic(logMessage)
+			requestLogger.WithFields(entryFields).Panic(logMessage)
 		default:
-			logger.WithFields(entryFields).Info(logMessage)
+			requestLogger.WithFields(entryFields).Info(logMessage)
 		}
 	}
 }
 
+// RequestIDMiddleware keeps the X-Request-ID sent by the caller or gateway when it is valid and
+// generates one otherwise, then continues the W3C trace of the caller or starts a new one. Both are
+// stored on the request context, see the correlation package, and the request ID is echoed back.
+// With tracing set up, the span opened by otelgin is the span of the request.
 func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		uuid, _ := uuid.NewV7()
-		requestID := uuid.String()
+		requestID := c.GetHeader(REQUEST_HEADER)
+		if !correlation.ValidRequestID(requestID) {
+			uuid, _ := uuid.NewV7()
+			requestID = uuid.String()
+		}
+		ctx := correlation.WithRequestID(c.Request.Context(), requestID)
+		var span correlation.TraceParent
+		var state string
+		// otelgin opens a local span when tracing is set up, the no-op tracer only passes the caller's on
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() && !spanContext.IsRemote() {
+			span = correlation.TraceParent{TraceID: spanContext.TraceID().String(), ParentID: spanContext.SpanID().String(),
+				Flags: spanContext.TraceFlags().String()}
+			state = spanContext.TraceState().String()
+		} else {
+			span = correlation.NewTraceParent()
+			if parent, ok := correlation.ParseTraceparent(c.GetHeader(correlation.TraceparentHeader)); ok {
+				span = parent.Child()
+				state = strings.Join(c.Request.Header.Values(correlation.TracestateHeader), ",")
+			}
+			// Hand the span to OpenTelemetry too, so that instrumented clients propagate it
+			ctx = trace.ContextWithSpanContext(ctx, spanContextOf(span, state))
+		}
+		c.Request = c.Request.WithContext(correlation.WithTrace(ctx, span, state))
+
 		c.Set(REQUEST_ID, requestID)
+		c.Set(TRACE_ID, span.TraceID)
 		c.Writer.Header().Set(REQUEST_HEADER, requestID)
 		c.Next()
 	}
 }
 
-func TimeoutMiddleware() gin.HandlerFunc {
-	timeoutDuration, _ := time.ParseDuration(utils.GetEnvAsString("REQUEST_TIMEOUT_SEC", "120s"))
+func TimeoutMiddleware(timeoutDuration time.Duration) gin.HandlerFunc {
 	//Setting request timeout
 	return timeout.New(
 		timeout.WithTimeout(timeoutDuration),
 		timeout.WithHandler(func(c *gin.Context) {
+			// Bound the request context too, so database calls are cancelled once the timeout fires
+			ctx, cancel := context.WithTimeout(c.Request.Context(), timeoutDuration)
+			defer cancel()
+			c.Request = c.Request.WithContext(ctx)
 			c.Next()
 		}),
 		timeout.WithResponse(func(c *gin.Context) {
 			c.AbortWithStatusJSON(http.StatusRequestTimeout, gin.H{"error": "Request Timeout"})
 		}))
 }
+
+func spanContextOf(span correlation.TraceParent, state string) trace.SpanContext {
+	traceID, _ := trace.TraceIDFromHex(span.TraceID)
+	spanID, _ := trace.SpanIDFromHex(span.ParentID)
+	// An invalid tracestate is dropped, as the W3C spec allows
+	traceState, _ := trace.ParseTraceState(state)
+	flags := trace.TraceFlags(0)
+	if span.Flags == "01" {
+		flags = trace.FlagsSampled
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: flags, TraceState: traceState})
+}
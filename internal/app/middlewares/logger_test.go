@@ -0,0 +1,125 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"starter/internal/app/correlation"
+	"starter/internal/app/logging"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func serveWithRequestID(header http.Header) (*httptest.ResponseRecorder, string, correlation.TraceParent, string) {
+	gin.SetMode(gin.TestMode)
+	var requestID, state string
+	var span correlation.TraceParent
+	router := gin.New()
+	router.Use(RequestIDMiddleware())
+	router.GET("/", func(c *gin.Context) {
+		requestID = correlation.RequestID(c.Request.Context())
+		span, state, _ = correlation.Trace(c.Request.Context())
+	})
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	for name, values := range header {
+		for _, value := range values {
+			request.Header.Add(name, value)
+		}
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder, requestID, span, state
+}
+
+func TestRequestIDMiddleware_HonorsInboundHeaders(t *testing.T) {
+	recorder, requestID, span, state := serveWithRequestID(http.Header{
+		REQUEST_HEADER:                {"gateway-123"},
+		correlation.TraceparentHeader: {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		correlation.TracestateHeader:  {"a=1", "b=2"},
+	})
+	assert.Equal(t, "gateway-123", requestID)
+	assert.Equal(t, "gateway-123", recorder.Header().Get(REQUEST_HEADER))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID)
+	assert.NotEqual(t, "00f067aa0ba902b7", span.ParentID, "the server opens its own span")
+	assert.Equal(t, "a=1,b=2", state)
+}
+
+func TestRequestIDMiddleware_ReplacesInvalidHeaders(t *testing.T) {
+	recorder, requestID, span, state := serveWithRequestID(http.Header{
+		REQUEST_HEADER:                {"bad id\r\n"},
+		correlation.TraceparentHeader: {"garbage"},
+		correlation.TracestateHeader:  {"a=1"},
+	})
+	assert.True(t, correlation.ValidRequestID(requestID))
+	assert.NotEqual(t, "bad id\r\n", requestID)
+	assert.Equal(t, requestID, recorder.Header().Get(REQUEST_HEADER))
+	assert.Len(t, span.TraceID, 32)
+	assert.Empty(t, state, "tracestate without a valid traceparent is dropped")
+}
+
+func TestRequestIDMiddleware_UsesOtelSpan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	var span correlation.TraceParent
+	var otelSpan trace.SpanContext
+	router := gin.New()
+	router.Use(otelgin.Middleware("test", otelgin.WithTracerProvider(provider),
+		otelgin.WithPropagators(propagation.TraceContext{})))
+	router.Use(RequestIDMiddleware())
+	router.GET("/", func(c *gin.Context) {
+		span, _, _ = correlation.Trace(c.Request.Context())
+		otelSpan = trace.SpanContextFromContext(c.Request.Context())
+	})
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(correlation.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Len(t, recorder.Ended(), 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID)
+	assert.Equal(t, otelSpan.SpanID().String(), span.ParentID, "logs and outbound calls name the otelgin span")
+}
+
+func TestRequestIDMiddleware_SharesSpanWithOtel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var span correlation.TraceParent
+	var otelSpan trace.SpanContext
+	router := gin.New()
+	router.Use(RequestIDMiddleware())
+	router.GET("/", func(c *gin.Context) {
+		span, _, _ = correlation.Trace(c.Request.Context())
+		otelSpan = trace.SpanContextFromContext(c.Request.Context())
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, span.TraceID, otelSpan.TraceID().String())
+	assert.Equal(t, span.ParentID, otelSpan.SpanID().String())
+}
+
+func TestLoggerMiddleware_StoresRequestEntry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, hook := test.NewNullLogger()
+	router := gin.New()
+	router.Use(RequestIDMiddleware(), LoggerMiddleware(logger))
+	router.GET("/user/:id", func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Info("from the handler")
+	})
+	request := httptest.NewRequest(http.MethodGet, "/user/42", nil)
+	request.Header.Set(REQUEST_HEADER, "req-42")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	entries := hook.AllEntries()
+	assert.Len(t, entries, 2, "the handler's line and the request line")
+	for _, entry := range entries {
+		assert.Equal(t, "req-42", entry.Data["requestId"])
+		assert.Equal(t, "/user/:id", entry.Data["route"])
+		assert.NotEmpty(t, entry.Data["traceId"])
+	}
+}
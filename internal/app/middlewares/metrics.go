@@ -0,0 +1,18 @@
+package middlewares
+
+import (
+	"starter/internal/app/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware counts and times requests by route template, method and status code.
+// Routes are labelled by their template, /user/:id, as raw paths would grow the series without bound.
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		metrics.ObserveRequest(c.FullPath(), c.Request.Method, c.Writer.Status(), time.Since(start))
+	}
+}
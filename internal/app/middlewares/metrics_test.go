@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requestCount reads http_requests_total for one set of labels from the default registry.
+func requestCount(t *testing.T, labels map[string]string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "http_requests_total" {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, pair := range metric.GetLabel() {
+				if labels[pair.GetName()] != pair.GetValue() {
+					continue metrics
+				}
+			}
+			return metric.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestMetricsMiddleware_LabelsRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(MetricsMiddleware())
+	router.GET("/user/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	labels := map[string]string{"route": "/user/:id", "method": http.MethodGet, "status": "204"}
+	before := requestCount(t, labels)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user/42", nil))
+	assert.Equal(t, before+1, requestCount(t, labels))
+	assert.Zero(t, requestCount(t, map[string]string{"route": "/user/42", "method": http.MethodGet, "status": "204"}),
+		"raw paths are never used as labels")
+}
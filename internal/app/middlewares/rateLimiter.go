@@ -1,18 +1,93 @@
 package middlewares
 
 import (
+	"math"
 	"net/http"
+	"starter/internal/app/constants"
+	"starter/internal/app/logging"
+	"starter/internal/app/ratelimit"
+	"starter/internal/app/utils"
+	"starter/internal/config"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
 )
 
-func RateLimitMiddleware(limiter *rate.Limiter) gin.HandlerFunc {
+const (
+	RATE_LIMIT_LIMIT_HEADER     = "X-RateLimit-Limit"
+	RATE_LIMIT_REMAINING_HEADER = "X-RateLimit-Remaining"
+	RATE_LIMIT_RESET_HEADER     = "X-RateLimit-Reset"
+	RETRY_AFTER_HEADER          = "Retry-After"
+)
+
+// RateLimiter applies the rule of a route group to each client, counting in store.
+type RateLimiter struct {
+	name  string
+	rule  config.RateLimitRule
+	store ratelimit.Store
+	now   func() time.Time
+}
+
+// NewRateLimiter returns nil for a disabled rule, RateLimitMiddleware then lets every request through.
+func NewRateLimiter(name string, rule config.RateLimitRule, store ratelimit.Store) *RateLimiter {
+	if rule.Disabled() {
+		return nil
+	}
+	return &RateLimiter{name: name, rule: rule, store: store, now: time.Now}
+}
+
+// RateLimitKey identifies the client a request is counted against: the authenticated user, else
+// the client IP.
+func RateLimitKey(c *gin.Context) string {
+	if user, ok := GetAuthUser(c); ok {
+		return "user:" + strconv.FormatInt(user.ID, 10)
+	}
+	return ClientIPKey(c)
+}
+
+// ClientIPKey counts every request against its client IP, authenticated or not.
+func ClientIPKey(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitMiddleware counts each request against the bucket of its client and rejects it with
+// 429 once the bucket is empty. Chain it after AuthMiddleware to limit users rather than IPs.
+func RateLimitMiddleware(limiter *RateLimiter) gin.HandlerFunc {
+	return rateLimit(limiter, RateLimitKey)
+}
+
+// ClientIPRateLimitMiddleware limits each client IP, to chain before AuthMiddleware so that requests
+// it rejects, such as those probing tokens, are limited too.
+func ClientIPRateLimitMiddleware(limiter *RateLimiter) gin.HandlerFunc {
+	return rateLimit(limiter, ClientIPKey)
+}
+
+func rateLimit(limiter *RateLimiter, key func(*gin.Context) string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if !limiter.Allow() {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		decision, err := limiter.store.Take(c.Request.Context(), limiter.name+"|"+key(c), limiter.rule, limiter.now())
+		if err != nil {
+			// Fail open, an unavailable store must not take the API down with it
+			logging.FromContext(c.Request.Context()).Warnf("Rate limit of %s not applied: %v", limiter.name, err)
+			c.Next()
+			return
+		}
+		c.Header(RATE_LIMIT_LIMIT_HEADER, strconv.Itoa(decision.Limit))
+		c.Header(RATE_LIMIT_REMAINING_HEADER, strconv.Itoa(decision.Remaining))
+		c.Header(RATE_LIMIT_RESET_HEADER, strconv.Itoa(ceilSeconds(decision.Reset)))
+		if !decision.Allowed {
+			c.Header(RETRY_AFTER_HEADER, strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+			utils.ErrorResponse(c, http.StatusTooManyRequests, constants.TOO_MANY_REQUESTS)
 			return
 		}
 		c.Next()
 	}
 }
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
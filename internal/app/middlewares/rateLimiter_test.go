@@ -0,0 +1,97 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"starter/internal/app/logging"
+	"starter/internal/app/models"
+	"starter/internal/app/ratelimit"
+	"starter/internal/app/ratelimit/mocks"
+	"starter/internal/config"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestLimiter(rule config.RateLimitRule, store ratelimit.Store) *RateLimiter {
+	limiter := NewRateLimiter("test", rule, store)
+	limiter.now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+	return limiter
+}
+
+func TestRateLimiter_DisabledRule(t *testing.T) {
+	assert.Nil(t, NewRateLimiter("off", config.RateLimitRule{}, ratelimit.NewMemoryStore(time.Minute)))
+}
+
+func TestRateLimitMiddleware_Headers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := newTestLimiter(config.RateLimitRule{Requests: 1, Period: time.Minute}, ratelimit.NewMemoryStore(time.Minute))
+	router := gin.New()
+	router.GET("/", RateLimitMiddleware(limiter), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	first := httptest.NewRecorder()
+	router.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "1", first.Header().Get(RATE_LIMIT_LIMIT_HEADER))
+	assert.Equal(t, "0", first.Header().Get(RATE_LIMIT_REMAINING_HEADER))
+	assert.Equal(t, "60", first.Header().Get(RATE_LIMIT_RESET_HEADER))
+
+	second := httptest.NewRecorder()
+	router.ServeHTTP(second, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	assert.Equal(t, "60", second.Header().Get(RETRY_AFTER_HEADER))
+}
+
+func TestRateLimitMiddleware_FailsOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := mocks.NewStore(t)
+	store.On("Take", mock.Anything, "test|ip:192.0.2.1", mock.Anything, mock.Anything).
+		Return(ratelimit.Decision{}, errors.New("connection refused"))
+	logger, logs := test.NewNullLogger()
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(logging.WithEntry(c.Request.Context(), logger.WithField("requestId", "r-1")))
+	})
+	router.GET("/", RateLimitMiddleware(newTestLimiter(config.RateLimitRule{Requests: 1, Period: time.Minute}, store)),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get(RATE_LIMIT_LIMIT_HEADER))
+	if assert.NotNil(t, logs.LastEntry()) {
+		assert.Equal(t, "r-1", logs.LastEntry().Data["requestId"], "the warning carries the request fields")
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.RemoteAddr = "10.0.0.7:1234"
+	assert.Equal(t, "ip:10.0.0.7", RateLimitKey(c))
+
+	c.Set(AUTH_USER, &models.User{ID: 42})
+	assert.Equal(t, "user:42", RateLimitKey(c))
+	assert.Equal(t, "ip:10.0.0.7", ClientIPKey(c))
+}
+
+func TestClientIPRateLimitMiddleware_LimitsRejectedRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := newTestLimiter(config.RateLimitRule{Requests: 1, Period: time.Minute}, ratelimit.NewMemoryStore(time.Minute))
+	router := gin.New()
+	unauthorized := func(c *gin.Context) { c.AbortWithStatus(http.StatusUnauthorized) }
+	router.GET("/", ClientIPRateLimitMiddleware(limiter), unauthorized)
+
+	first := httptest.NewRecorder()
+	router.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, first.Code)
+
+	second := httptest.NewRecorder()
+	router.ServeHTTP(second, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+}
@@ -0,0 +1,305 @@
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"starter/internal/config"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+//go:embed sql/*.sql
+var migrationFiles embed.FS
+
+// lockID is the Postgres advisory lock key shared by every migration runner, so that two
+// instances starting together apply each migration only once.
+const lockID int64 = 7243100318722305
+
+const createTableSQL = `CREATE TABLE IF NOT EXISTS "public"."schema_migrations" (
+	"version"    BIGINT      PRIMARY KEY,
+	"name"       TEXT        NOT NULL,
+	"applied_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`
+
+// Migration is one versioned schema change read from sql/<version>_<name>.(up|down).sql.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status reports whether a migration has been applied. AppliedAt is nil for pending migrations.
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         config.DBPool
+	migrations []Migration
+}
+
+// NewMigrator returns a Migrator over the migrations embedded in the binary.
+func NewMigrator(db config.DBPool) (*Migrator, error) {
+	migrations, err := Load(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads the up and down scripts of fsys/sql and returns them ordered by version.
+// Every version needs both scripts and a single name.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		version, name, direction, err := parseFileName(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		content, err := fs.ReadFile(fsys, path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, name)
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseFileName splits 0001_create_users.up.sql into its version, name and direction.
+func parseFileName(fileName string) (int64, string, string, error) {
+	base, found := strings.CutSuffix(fileName, ".sql")
+	if !found {
+		return 0, "", "", fmt.Errorf("migration %s is not a .sql file", fileName)
+	}
+	dot := strings.LastIndex(base, ".")
+	if dot < 0 || (base[dot+1:] != "up" && base[dot+1:] != "down") {
+		return 0, "", "", fmt.Errorf("migration %s must end in .up.sql or .down.sql", fileName)
+	}
+	direction := base[dot+1:]
+	versionPart, name, found := strings.Cut(base[:dot], "_")
+	if !found || name == "" {
+		return 0, "", "", fmt.Errorf("migration %s must be named <version>_<name>", fileName)
+	}
+	version, err := strconv.ParseInt(versionPart, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, "", "", fmt.Errorf("migration %s has an invalid version", fileName)
+	}
+	return version, name, direction, nil
+}
+
+// Up applies every pending migration in order and returns how many were applied.
+// Each migration runs in its own transaction, so a failure keeps the earlier ones.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+	applied := 0
+	for _, migration := range m.migrations {
+		var done bool
+		err := m.withLock(ctx, func(tx pgx.Tx) error {
+			// Checked under the lock, another runner may have applied it meanwhile
+			exists, err := isApplied(ctx, tx, migration.Version)
+			if err != nil || exists {
+				return err
+			}
+			logrus.Infof("Applying migration %d_%s", migration.Version, migration.Name)
+			if _, err := tx.Exec(ctx, migration.Up); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			if _, err := tx.Exec(ctx, `INSERT INTO "public"."schema_migrations" ("version", "name") VALUES ($1, $2)`,
+				migration.Version, migration.Name); err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+			}
+			done = true
+			return nil
+		})
+		if err != nil {
+			return applied, err
+		}
+		if done {
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+// Down reverts the latest applied migrations, at most steps of them, and returns how many were reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+	reverted := 0
+	for reverted < steps {
+		var done bool
+		err := m.withLock(ctx, func(tx pgx.Tx) error {
+			var version int64
+			err := tx.QueryRow(ctx, `SELECT "version" FROM "public"."schema_migrations" ORDER BY "version" DESC LIMIT 1`).Scan(&version)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read the latest migration: %w", err)
+			}
+			migration, ok := m.find(version)
+			if !ok {
+				return fmt.Errorf("migration %d is applied but unknown to this build", version)
+			}
+			logrus.Infof("Reverting migration %d_%s", migration.Version, migration.Name)
+			if _, err := tx.Exec(ctx, migration.Down); err != nil {
+				return fmt.Errorf("revert of migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			if _, err := tx.Exec(ctx, `DELETE FROM "public"."schema_migrations" WHERE "version"=$1`, version); err != nil {
+				return fmt.Errorf("failed to unrecord migration %d: %w", version, err)
+			}
+			done = true
+			return nil
+		})
+		if err != nil {
+			return reverted, err
+		}
+		if !done {
+			break
+		}
+		reverted++
+	}
+	return reverted, nil
+}
+
+// Status lists every known migration along with the applied ones missing from this build.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := m.db.Query(ctx, `SELECT "version", "name", "applied_at" FROM "public"."schema_migrations"`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	byVersion := map[int64]*Status{}
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = &Status{Version: migration.Version, Name: migration.Name}
+	}
+	for rows.Next() {
+		var status Status
+		var appliedAt time.Time
+		if err := rows.Scan(&status.Version, &status.Name, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		if known, ok := byVersion[status.Version]; ok {
+			known.AppliedAt = &appliedAt
+		} else {
+			status.AppliedAt = &appliedAt
+			byVersion[status.Version] = &status
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	statuses := make([]Status, 0, len(byVersion))
+	for _, status := range byVersion {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Seed runs the given SQL script in a single transaction.
+func (m *Migrator) Seed(ctx context.Context, script string) error {
+	return m.withLock(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, script); err != nil {
+			return fmt.Errorf("seed failed: %w", err)
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	return m.withLock(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, createTableSQL); err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// withLock runs fn in a transaction holding the migration advisory lock. The lock is
+// released by Postgres when the transaction ends, whatever the outcome.
+func (m *Migrator) withLock(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration transaction: %w", err)
+	}
+	defer func() {
+		// No-op once committed
+		_ = tx.Rollback(context.Background())
+	}()
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("failed to acquire the migration lock: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit migration transaction: %w", err)
+	}
+	return nil
+}
+
+func isApplied(ctx context.Context, tx pgx.Tx, version int64) (bool, error) {
+	var exists bool
+	err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM "public"."schema_migrations" WHERE "version"=$1)`, version).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check migration %d: %w", version, err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,100 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Load_Embedded(t *testing.T) {
+	migrations, err := Load(migrationFiles)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, migrations)
+	for i, migration := range migrations {
+		assert.NotEmpty(t, migration.Up)
+		assert.NotEmpty(t, migration.Down)
+		if i > 0 {
+			assert.Greater(t, migration.Version, migrations[i-1].Version)
+		}
+	}
+}
+
+func Test_Load_Invalid(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"missing down": {"sql/0001_a.up.sql": {Data: []byte("SELECT 1")}},
+		"bad version":  {"sql/x_a.up.sql": {Data: []byte("SELECT 1")}, "sql/x_a.down.sql": {Data: []byte("SELECT 1")}},
+		"bad suffix":   {"sql/0001_a.sql": {Data: []byte("SELECT 1")}},
+		"two names":    {"sql/0001_a.up.sql": {Data: []byte("SELECT 1")}, "sql/0001_b.down.sql": {Data: []byte("SELECT 1")}},
+	}
+	for name, fsys := range cases {
+		_, err := Load(fsys)
+		assert.NotNil(t, err, name)
+	}
+}
+
+func Test_Up_AppliesPending(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	defer dbMock.Close()
+	migrator := &Migrator{db: dbMock, migrations: []Migration{
+		{Version: 1, Name: "a", Up: "CREATE a", Down: "DROP a"},
+		{Version: 2, Name: "b", Up: "CREATE b", Down: "DROP b"},
+	}}
+	expectLocked := func() {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(`SELECT pg_advisory_xact_lock`).WithArgs(lockID).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	}
+	expectLocked()
+	dbMock.ExpectExec(`CREATE TABLE IF NOT EXISTS`).WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	dbMock.ExpectCommit()
+
+	expectLocked()
+	dbMock.ExpectQuery(`SELECT EXISTS`).WithArgs(int64(1)).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	dbMock.ExpectCommit()
+
+	expectLocked()
+	dbMock.ExpectQuery(`SELECT EXISTS`).WithArgs(int64(2)).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	dbMock.ExpectExec(`CREATE b`).WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	dbMock.ExpectExec(`INSERT INTO`).WithArgs(int64(2), "b").WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	dbMock.ExpectCommit()
+
+	applied, err := migrator.Up(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, applied)
+	if e := dbMock.ExpectationsWereMet(); e != nil {
+		t.Errorf("there were unfulfilled expectations: %s", e)
+	}
+}
+
+func Test_Up_RollsBackFailedMigration(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	defer dbMock.Close()
+	migrator := &Migrator{db: dbMock, migrations: []Migration{
+		{Version: 1, Name: "a", Up: "CREATE a", Down: "DROP a"},
+	}}
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(`SELECT pg_advisory_xact_lock`).WithArgs(lockID).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	dbMock.ExpectExec(`CREATE TABLE IF NOT EXISTS`).WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	dbMock.ExpectCommit()
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(`SELECT pg_advisory_xact_lock`).WithArgs(lockID).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	dbMock.ExpectQuery(`SELECT EXISTS`).WithArgs(int64(1)).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	dbMock.ExpectExec(`CREATE a`).WillReturnError(errors.New("syntax error"))
+	dbMock.ExpectRollback()
+
+	applied, err := migrator.Up(context.Background())
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, applied)
+	if e := dbMock.ExpectationsWereMet(); e != nil {
+		t.Errorf("there were unfulfilled expectations: %s", e)
+	}
+}
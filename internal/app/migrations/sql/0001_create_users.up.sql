@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS "public"."users" (
+    "id"                 BIGSERIAL PRIMARY KEY,
+    "userEmailId"        VARCHAR(320) NOT NULL,
+    "encrypted_password" TEXT         NOT NULL,
+    "inserted_at"        TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    "updated_at"         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    "userDisplayName"    VARCHAR(255) NOT NULL DEFAULT '',
+    "userFirstName"      VARCHAR(255) NOT NULL DEFAULT '',
+    "userLastName"       VARCHAR(255) NOT NULL DEFAULT '',
+    "userRole"           VARCHAR(32)  NOT NULL DEFAULT 'viewer',
+    "stored_salt"        TEXT         NOT NULL,
+    CONSTRAINT "users_userEmailId_key" UNIQUE ("userEmailId"),
+    CONSTRAINT "users_userRole_check" CHECK ("userRole" IN ('admin', 'tester', 'viewer'))
+);
+
+CREATE INDEX IF NOT EXISTS "users_userRole_idx" ON "public"."users" ("userRole");
+CREATE INDEX IF NOT EXISTS "users_inserted_at_idx" ON "public"."users" ("inserted_at");
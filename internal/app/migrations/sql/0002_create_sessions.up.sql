@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS "public"."sessions" (
+    "id"          TEXT        PRIMARY KEY,
+    "userId"      BIGINT      NOT NULL REFERENCES "public"."users" ("id") ON DELETE CASCADE,
+    "inserted_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    "expires_at"  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "sessions_userId_idx" ON "public"."sessions" ("userId");
+CREATE INDEX IF NOT EXISTS "sessions_expires_at_idx" ON "public"."sessions" ("expires_at");
@@ -0,0 +1,10 @@
+-- Values stay encrypted, rows whose ciphertext exceeds the former lengths make this fail
+DROP INDEX IF EXISTS "public"."users_userEmailIndex_key";
+
+ALTER TABLE "public"."users"
+    DROP COLUMN IF EXISTS "userEmailIndex",
+    ALTER COLUMN "userEmailId" TYPE VARCHAR(320),
+    ALTER COLUMN "userDisplayName" TYPE VARCHAR(255),
+    ALTER COLUMN "userFirstName" TYPE VARCHAR(255),
+    ALTER COLUMN "userLastName" TYPE VARCHAR(255),
+    ADD CONSTRAINT "users_userEmailId_key" UNIQUE ("userEmailId");
@@ -0,0 +1,12 @@
+-- Email and names are stored encrypted by the application. Ciphertext does not fit the
+-- original lengths, and lookups by email go through the "userEmailIndex" blind index.
+-- Existing rows are encrypted and indexed by `main reencrypt-users`.
+ALTER TABLE "public"."users"
+    ALTER COLUMN "userEmailId" TYPE TEXT,
+    ALTER COLUMN "userDisplayName" TYPE TEXT,
+    ALTER COLUMN "userFirstName" TYPE TEXT,
+    ALTER COLUMN "userLastName" TYPE TEXT,
+    ADD COLUMN IF NOT EXISTS "userEmailIndex" CHAR(64),
+    DROP CONSTRAINT IF EXISTS "users_userEmailId_key";
+
+CREATE UNIQUE INDEX IF NOT EXISTS "users_userEmailIndex_key" ON "public"."users" ("userEmailIndex");
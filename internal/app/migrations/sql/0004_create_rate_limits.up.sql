@@ -0,0 +1,8 @@
+-- Shared rate limit counters, see internal/app/ratelimit. Losing them on a crash only resets
+-- the limits, so the table skips the write-ahead log.
+CREATE UNLOGGED TABLE IF NOT EXISTS "public"."rate_limits" (
+    "key" TEXT   PRIMARY KEY,
+    "tat" BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "rate_limits_tat_idx" ON "public"."rate_limits" ("tat");
@@ -0,0 +1,60 @@
+package models
+
+// Role is the access level stored in the userRole column.
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleTester Role = "tester"
+	RoleViewer Role = "viewer"
+)
+
+// Permission is a single capability granted to one or more roles.
+type Permission string
+
+const (
+	PermissionManageUsers Permission = "users:manage"
+	PermissionViewUsers   Permission = "users:view"
+	PermissionManageLogs  Permission = "logs:manage"
+	PermissionViewLogs    Permission = "logs:view"
+	PermissionRunTests    Permission = "tests:run"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionManageUsers,
+		PermissionViewUsers,
+		PermissionManageLogs,
+		PermissionViewLogs,
+		PermissionRunTests,
+	},
+	RoleTester: {
+		PermissionViewUsers,
+		PermissionViewLogs,
+		PermissionRunTests,
+	},
+	RoleViewer: {
+		PermissionViewUsers,
+	},
+}
+
+// IsValid reports whether the role is one of the known roles.
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Permissions returns the permission set granted to the role.
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+// HasPermission reports whether the role grants the given permission.
+func (r Role) HasPermission(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"net/http"
+	"net/mail"
+	"starter/internal/app/constants"
+	"starter/internal/app/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Session struct {
+	ID         string    `json:"id"`
+	UserID     int64     `json:"userId"`
+	InsertedAt time.Time `json:"inserted_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// AuthClaims are the claims carried by the access token issued on login.
+type AuthClaims struct {
+	SessionID string `json:"sessionId"`
+	UserID    int64  `json:"userId"`
+	UserRole  Role   `json:"userRole"`
+	jwt.RegisteredClaims
+}
+
+type LoginRequestDto struct {
+	UserEmailId  string `json:"userEmailId"`
+	UserPassword string `json:"userPassword"`
+}
+
+type LoginResponseDto struct {
+	AccessToken string    `json:"accessToken"`
+	TokenType   string    `json:"tokenType"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+func (l *LoginRequestDto) Validate() *utils.ErrorMessage {
+	if _, err := mail.ParseAddress(l.UserEmailId); err != nil {
+		return &utils.ErrorMessage{Message: constants.WRONG_CREDENTIALS, StatusCode: http.StatusBadRequest}
+	}
+	if len(l.UserPassword) == 0 {
+		return &utils.ErrorMessage{Message: constants.WRONG_CREDENTIALS, StatusCode: http.StatusBadRequest}
+	}
+	return nil
+}
@@ -9,17 +9,19 @@ import (
 	"time"
 )
 
+// User maps a row of the users table, including the credential columns.
+// Responses must go through ToResponseDto instead of serialising it directly.
 type User struct {
 	ID                int64     `json:"id"`
 	UserEmailId       string    `json:"userEmailId"`
-	EncryptedPassword string    `json:"encrypted_password"`
+	EncryptedPassword string    `json:"-"`
 	InsertedAt        time.Time `json:"inserted_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
 	UserDisplayName   string    `json:"userDisplayName"`
 	UserFirstName     string    `json:"userFirstName"`
 	UserLastName      string    `json:"userLastName"`
-	UserRole          string    `json:"userRole"`
-	StoredSalt        string    `json:"stored_salt"`
+	UserRole          Role      `json:"userRole"`
+	StoredSalt        string    `json:"-"`
 }
 
 type UserResponseDto struct {
@@ -32,7 +34,75 @@ type UserResponseDto struct {
 	UserLastName    string `json:"userLastName"`
 	ViewerRole      bool   `json:"viewerRole"`
 	UserId          int64  `json:"userId"`
-	UserRole        string `json:"userRole"`
+	UserRole        Role   `json:"userRole"`
+}
+
+// ToResponseDto maps the user into the DTO returned to API callers, leaving out credentials.
+// The role flags are derived from the permissions granted to the user's role.
+func (u *User) ToResponseDto() *UserResponseDto {
+	return &UserResponseDto{
+		UserId:          u.ID,
+		UserEmailId:     u.UserEmailId,
+		UserDisplayName: u.UserDisplayName,
+		UserFirstName:   u.UserFirstName,
+		UserLastName:    u.UserLastName,
+		UserRole:        u.UserRole,
+		AdminRole:       u.UserRole == RoleAdmin,
+		CanViewLogsRole: u.UserRole.HasPermission(PermissionViewLogs),
+		TesterRole:      u.UserRole.HasPermission(PermissionRunTests),
+		ViewerRole:      u.UserRole.HasPermission(PermissionViewUsers),
+	}
+}
+
+// ToUserResponseDtos maps rows returned by the user repository into response DTOs.
+func ToUserResponseDtos(rows []interface{}) []*UserResponseDto {
+	users := make([]*UserResponseDto, 0, len(rows))
+	for _, row := range rows {
+		if user, ok := row.(*User); ok {
+			users = append(users, user.ToResponseDto())
+		}
+	}
+	return users
+}
+
+// UserSortFields are the user columns the listing endpoint may be sorted by. The email and
+// names are stored encrypted, their order in the database means nothing.
+var UserSortFields = []string{"id", "inserted_at", "updated_at", "userRole"}
+
+// EncryptedUserSortFields were sortable before the email and names were encrypted, they are
+// refused with an explanation rather than as an unknown field.
+var EncryptedUserSortFields = []string{"email", "name", "userEmailId", "userDisplayName", "userFirstName", "userLastName"}
+
+// UserFilter narrows down the users returned by the listing endpoint. Zero values are ignored.
+type UserFilter struct {
+	Role         Role
+	Email        string
+	CreatedAfter time.Time
+}
+
+// NewUserFilter validates the raw query parameters and builds a UserFilter from them. The email
+// is matched exactly through the blind index, so anything but a complete address is refused.
+func NewUserFilter(role string, email string, createdAfter string) (*UserFilter, *utils.ErrorMessage) {
+	filter := &UserFilter{Role: Role(role), Email: email}
+	if len(role) > 0 && !filter.Role.IsValid() {
+		return nil, &utils.ErrorMessage{Message: constants.INVALID_ROLE, StatusCode: http.StatusBadRequest}
+	}
+	if len(email) > 0 {
+		if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+			return nil, &utils.ErrorMessage{Message: constants.EMAIL_FILTER_NOT_ADDRESS, StatusCode: http.StatusBadRequest}
+		}
+	}
+	if len(createdAfter) > 0 {
+		t, err := time.Parse(time.RFC3339, createdAfter)
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, createdAfter)
+		}
+		if err != nil {
+			return nil, &utils.ErrorMessage{Message: fmt.Sprintf(constants.INVALID_FILTER, "createdAfter"), StatusCode: http.StatusBadRequest}
+		}
+		filter.CreatedAfter = t
+	}
+	return filter, nil
 }
 
 type UserRequestDto struct {
@@ -41,7 +111,7 @@ type UserRequestDto struct {
 	UserDisplayName string `json:"userDisplayName"`
 	UserFirstName   string `json:"userFirstName"`
 	UserLastName    string `json:"userLastName"`
-	UserRole        string `json:"userRole"`
+	UserRole        Role   `json:"userRole"`
 }
 
 func (c *UserRequestDto) Validate() *utils.ErrorMessage {
@@ -67,5 +137,49 @@ func (c *UserRequestDto) Validate() *utils.ErrorMessage {
 	if len(c.UserRole) == 0 {
 		return &utils.ErrorMessage{Message: fmt.Sprintf(constants.EMPTY_FIELD, "UserRole"), StatusCode: http.StatusBadRequest}
 	}
+	if !c.UserRole.IsValid() {
+		return &utils.ErrorMessage{Message: constants.INVALID_ROLE, StatusCode: http.StatusBadRequest}
+	}
+	return nil
+}
+
+type UserUpdateDto struct {
+	UserEmailId     string `json:"userEmailId"`
+	UserDisplayName string `json:"userDisplayName"`
+	UserFirstName   string `json:"userFirstName"`
+	UserLastName    string `json:"userLastName"`
+}
+
+func (c *UserUpdateDto) Validate() *utils.ErrorMessage {
+	if _, err := mail.ParseAddress(c.UserEmailId); err != nil {
+		return &utils.ErrorMessage{Message: constants.INVALID_EMAILID, StatusCode: http.StatusBadRequest}
+	}
+	if len(c.UserDisplayName) == 0 {
+		return &utils.ErrorMessage{Message: fmt.Sprintf(constants.EMPTY_FIELD, "UserDisplayName"), StatusCode: http.StatusBadRequest}
+	}
+	if len(c.UserFirstName) == 0 {
+		return &utils.ErrorMessage{Message: fmt.Sprintf(constants.EMPTY_FIELD, "UserFirstName"), StatusCode: http.StatusBadRequest}
+	}
+	if len(c.UserLastName) == 0 {
+		return &utils.ErrorMessage{Message: fmt.Sprintf(constants.EMPTY_FIELD, "UserLastName"), StatusCode: http.StatusBadRequest}
+	}
+	return nil
+}
+
+type PasswordUpdateDto struct {
+	OldPassword string `json:"oldPassword"`
+	NewPassword string `json:"newPassword"`
+}
+
+func (c *PasswordUpdateDto) Validate() *utils.ErrorMessage {
+	if len(c.OldPassword) == 0 {
+		return &utils.ErrorMessage{Message: fmt.Sprintf(constants.EMPTY_FIELD, "OldPassword"), StatusCode: http.StatusBadRequest}
+	}
+	if len(c.NewPassword) < 8 {
+		return &utils.ErrorMessage{Message: constants.INVALID_PASSWORD, StatusCode: http.StatusBadRequest}
+	}
+	if c.NewPassword == c.OldPassword {
+		return &utils.ErrorMessage{Message: constants.PASSWORD_NOT_CHANGED, StatusCode: http.StatusBadRequest}
+	}
 	return nil
 }
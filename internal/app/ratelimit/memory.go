@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"starter/internal/config"
+	"sync"
+	"time"
+)
+
+type memoryStore struct {
+	sweepInterval time.Duration
+	mu            sync.Mutex
+	tats          map[string]time.Time
+	lastSweep     time.Time
+}
+
+// NewMemoryStore keeps the counters of this process only. Every sweepInterval, keys whose
+// budget is whole again are dropped, they would behave the same as unknown keys.
+func NewMemoryStore(sweepInterval time.Duration) Store {
+	return &memoryStore{sweepInterval: sweepInterval, tats: map[string]time.Time{}}
+}
+
+func (m *memoryStore) Take(_ context.Context, key string, rule config.RateLimitRule, now time.Time) (Decision, error) {
+	if err := ruleError(rule); err != nil {
+		return Decision{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(now)
+
+	decision, tat := gcra(m.tats[key], rule, now)
+	m.tats[key] = tat
+	return decision, nil
+}
+
+func (m *memoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < m.sweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, tat := range m.tats {
+		if !tat.After(now) {
+			delete(m.tats, key)
+		}
+	}
+}
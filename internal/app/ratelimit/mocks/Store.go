@@ -0,0 +1,61 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	config "starter/internal/config"
+
+	mock "github.com/stretchr/testify/mock"
+
+	ratelimit "starter/internal/app/ratelimit"
+
+	time "time"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// Take provides a mock function with given fields: ctx, key, rule, now
+func (_m *Store) Take(ctx context.Context, key string, rule config.RateLimitRule, now time.Time) (ratelimit.Decision, error) {
+	ret := _m.Called(ctx, key, rule, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Take")
+	}
+
+	var r0 ratelimit.Decision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, config.RateLimitRule, time.Time) (ratelimit.Decision, error)); ok {
+		return rf(ctx, key, rule, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, config.RateLimitRule, time.Time) ratelimit.Decision); ok {
+		r0 = rf(ctx, key, rule, now)
+	} else {
+		r0 = ret.Get(0).(ratelimit.Decision)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, config.RateLimitRule, time.Time) error); ok {
+		r1 = rf(ctx, key, rule, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"starter/internal/config"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// takeSQL runs GCRA in one statement so concurrent replicas cannot both spend the last request:
+// the row only moves when the new TAT stays within a period of now. Times are Unix microseconds,
+// $2 is now, $3 the emission interval and $4 the period.
+const takeSQL = `INSERT INTO "public"."rate_limits" AS r ("key", "tat")
+	VALUES ($1, $2 + $3)
+	ON CONFLICT ("key") DO UPDATE
+		SET "tat" = GREATEST(r."tat", $2) + $3
+		WHERE GREATEST(r."tat", $2) + $3 - $4 <= $2
+	RETURNING "tat"`
+
+const currentSQL = `SELECT "tat" FROM "public"."rate_limits" WHERE "key"=$1`
+
+const sweepSQL = `DELETE FROM "public"."rate_limits" WHERE "tat" <= $1`
+
+type postgresStore struct {
+	db            config.DBPool
+	sweepInterval time.Duration
+	mu            sync.Mutex
+	lastSweep     time.Time
+}
+
+// NewPostgresStore shares the counters of every replica through the rate_limits table.
+// Every sweepInterval, one Take also deletes the keys whose budget is whole again.
+func NewPostgresStore(db config.DBPool, sweepInterval time.Duration) Store {
+	return &postgresStore{db: db, sweepInterval: sweepInterval}
+}
+
+func (p *postgresStore) Take(ctx context.Context, key string, rule config.RateLimitRule, now time.Time) (Decision, error) {
+	if err := ruleError(rule); err != nil {
+		return Decision{}, err
+	}
+	// The table has microsecond precision, the decision must be computed on the same time
+	now = now.Truncate(time.Microsecond)
+	p.sweep(ctx, now)
+
+	interval := rule.Period / time.Duration(rule.Requests)
+	var tat int64
+	err := p.db.QueryRow(ctx, takeSQL, key, now.UnixMicro(), interval.Microseconds(), rule.Period.Microseconds()).Scan(&tat)
+	if err == nil {
+		// The stored TAT is the one gcra returns when allowing from the previous TAT
+		decision, _ := gcra(time.UnixMicro(tat).Add(-interval), rule, now)
+		decision.Allowed, decision.RetryAfter = true, 0
+		return decision, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return Decision{}, err
+	}
+	// Refused, the row was left as is
+	if err := p.db.QueryRow(ctx, currentSQL, key).Scan(&tat); err != nil {
+		return Decision{}, err
+	}
+	decision, _ := gcra(time.UnixMicro(tat), rule, now)
+	decision.Allowed = false
+	return decision, nil
+}
+
+func (p *postgresStore) sweep(ctx context.Context, now time.Time) {
+	p.mu.Lock()
+	if now.Sub(p.lastSweep) < p.sweepInterval {
+		p.mu.Unlock()
+		return
+	}
+	p.lastSweep = now
+	p.mu.Unlock()
+	if _, err := p.db.Exec(ctx, sweepSQL, now.UnixMicro()); err != nil {
+		logrus.Warnf("Failed to delete expired rate limits: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"starter/internal/config"
+	"time"
+)
+
+const (
+	MemoryStore   = "memory"
+	PostgresStore = "postgres"
+)
+
+// Decision is the outcome of counting one request against a rule.
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the client has its whole budget again
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, zero when Allowed
+	RetryAfter time.Duration
+}
+
+// Store counts requests per key. Implementations use GCRA, the generic cell rate algorithm:
+// each key only keeps its theoretical arrival time (TAT), the time at which its budget would
+// be whole again, which makes an update a single atomic compare and set.
+//
+//go:generate mockery --name Store
+type Store interface {
+	Take(ctx context.Context, key string, rule config.RateLimitRule, now time.Time) (Decision, error)
+}
+
+// NewStore returns the store selected by RATE_LIMIT_STORE. The memory store limits each
+// replica on its own, the postgres one shares the limits between every replica.
+func NewStore(db config.DBPool, rateLimitConfig config.RateLimitConfig) Store {
+	if rateLimitConfig.Store == PostgresStore {
+		return NewPostgresStore(db, rateLimitConfig.IdleTTL)
+	}
+	return NewMemoryStore(rateLimitConfig.IdleTTL)
+}
+
+// gcra applies the rule to a key whose current TAT is tat, the zero time for an unknown key.
+// It returns the decision and the TAT to store, which is tat itself when the request is refused.
+func gcra(tat time.Time, rule config.RateLimitRule, now time.Time) (Decision, time.Time) {
+	// Each request pushes the TAT by one emission interval, at most a whole period ahead of now
+	interval := rule.Period / time.Duration(rule.Requests)
+	if tat.Before(now) {
+		tat = now
+	}
+	newTAT := tat.Add(interval)
+	decision := Decision{Limit: rule.Requests}
+	if allowAt := newTAT.Add(-rule.Period); allowAt.After(now) {
+		decision.RetryAfter = allowAt.Sub(now)
+		decision.Reset = tat.Sub(now)
+		decision.Remaining = remaining(tat, rule, now, interval)
+		return decision, tat
+	}
+	decision.Allowed = true
+	decision.Reset = newTAT.Sub(now)
+	decision.Remaining = remaining(newTAT, rule, now, interval)
+	return decision, newTAT
+}
+
+func remaining(tat time.Time, rule config.RateLimitRule, now time.Time, interval time.Duration) int {
+	left := int((rule.Period - tat.Sub(now)) / interval)
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+func ruleError(rule config.RateLimitRule) error {
+	if rule.Requests <= 0 || rule.Period <= 0 {
+		return fmt.Errorf("invalid rate limit rule %d/%s", rule.Requests, rule.Period)
+	}
+	return nil
+}
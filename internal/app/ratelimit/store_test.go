@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"starter/internal/config"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestMemoryStore_BurstThenRefill(t *testing.T) {
+	store := NewMemoryStore(time.Minute)
+	rule := config.RateLimitRule{Requests: 2, Period: time.Second}
+	ctx := context.Background()
+
+	decision, _ := store.Take(ctx, "a", rule, start)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 1, decision.Remaining)
+	decision, _ = store.Take(ctx, "a", rule, start)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+	assert.Equal(t, time.Second, decision.Reset)
+
+	decision, _ = store.Take(ctx, "a", rule, start)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 500*time.Millisecond, decision.RetryAfter)
+	decision, _ = store.Take(ctx, "b", rule, start)
+	assert.True(t, decision.Allowed, "another key keeps its own budget")
+
+	decision, _ = store.Take(ctx, "a", rule, start.Add(500*time.Millisecond))
+	assert.True(t, decision.Allowed)
+}
+
+func TestMemoryStore_SweepsFullBudgets(t *testing.T) {
+	store := NewMemoryStore(time.Minute).(*memoryStore)
+	rule := config.RateLimitRule{Requests: 10, Period: time.Minute}
+	store.Take(context.Background(), "a", rule, start)
+	store.Take(context.Background(), "b", rule, start)
+	assert.Len(t, store.tats, 2)
+
+	store.Take(context.Background(), "c", rule, start.Add(2*time.Minute))
+	assert.Len(t, store.tats, 1)
+}
+
+func TestMemoryStore_RejectsInvalidRule(t *testing.T) {
+	_, err := NewMemoryStore(time.Minute).Take(context.Background(), "a", config.RateLimitRule{}, start)
+	assert.Error(t, err)
+}
+
+func TestPostgresStore_Allowed(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	defer dbMock.Close()
+	store := NewPostgresStore(dbMock, time.Minute)
+	rule := config.RateLimitRule{Requests: 4, Period: time.Second}
+	dbMock.ExpectExec(`DELETE FROM "public"."rate_limits" WHERE "tat" <= \$1`).
+		WithArgs(start.UnixMicro()).WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	dbMock.ExpectQuery(`INSERT INTO "public"."rate_limits"`).
+		WithArgs("auth|ip:1", start.UnixMicro(), int64(250000), int64(1000000)).
+		WillReturnRows(pgxmock.NewRows([]string{"tat"}).AddRow(start.Add(500 * time.Millisecond).UnixMicro()))
+
+	decision, err := store.Take(context.Background(), "auth|ip:1", rule, start)
+	assert.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 2, decision.Remaining)
+	assert.Equal(t, 500*time.Millisecond, decision.Reset)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestPostgresStore_Refused(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	defer dbMock.Close()
+	store := NewPostgresStore(dbMock, time.Minute).(*postgresStore)
+	store.lastSweep = start
+	rule := config.RateLimitRule{Requests: 4, Period: time.Second}
+	dbMock.ExpectQuery(`INSERT INTO "public"."rate_limits"`).
+		WithArgs("auth|ip:1", start.UnixMicro(), int64(250000), int64(1000000)).
+		WillReturnError(pgx.ErrNoRows)
+	dbMock.ExpectQuery(`SELECT "tat" FROM "public"."rate_limits"`).
+		WithArgs("auth|ip:1").
+		WillReturnRows(pgxmock.NewRows([]string{"tat"}).AddRow(start.Add(900 * time.Millisecond).UnixMicro()))
+
+	decision, err := store.Take(context.Background(), "auth|ip:1", rule, start)
+	assert.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+	assert.Equal(t, 150*time.Millisecond, decision.RetryAfter)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
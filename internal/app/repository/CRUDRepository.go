@@ -2,129 +2,219 @@ package Repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
 	"starter/internal/app/constants"
+	"starter/internal/app/logging"
+	"starter/internal/app/tracing"
 	"starter/internal/app/utils"
 	"starter/internal/config"
+	"time"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/sirupsen/logrus"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 //go:generate mockery --name CRUDRepository
 type CRUDRepository interface {
-	BeginTransaction() (pgx.Tx, *utils.ErrorMessage)
-	CommitTransaction(tx pgx.Tx, objectType string) *utils.ErrorMessage
+	BeginTransaction(ctx context.Context) (pgx.Tx, *utils.ErrorMessage)
+	CommitTransaction(ctx context.Context, tx pgx.Tx, objectType string) *utils.ErrorMessage
 	RollBackTransaction(tx pgx.Tx, objectType string) *utils.ErrorMessage
-	Delete(query string, objectType string, args ...any) *utils.ErrorMessage
-	Update(query string, objectType string, args ...any) *utils.ErrorMessage
-	Create(query string, objectType string, args ...any) (interface{}, *utils.ErrorMessage)
-	GetOne(query string, objectType string, mapper utils.RowMapperFunc, args ...any) (interface{}, *utils.ErrorMessage)
-	Get(query string, objectType string, mapper utils.RowMapperFunc, args ...any) ([]interface{}, *utils.ErrorMessage)
-	GetWithPagination(countSQL string, objectType string, finalSQL string, mapper utils.RowMapperFunc, pagination *utils.Pagination, args ...any) (*utils.Pagination, *utils.ErrorMessage)
+	Delete(ctx context.Context, query string, objectType string, args ...any) *utils.ErrorMessage
+	Update(ctx context.Context, query string, objectType string, args ...any) *utils.ErrorMessage
+	Create(ctx context.Context, query string, objectType string, args ...any) (interface{}, *utils.ErrorMessage)
+	GetOne(ctx context.Context, query string, objectType string, mapper utils.RowMapperFunc, args ...any) (interface{}, *utils.ErrorMessage)
+	Get(ctx context.Context, query string, objectType string, mapper utils.RowMapperFunc, args ...any) ([]interface{}, *utils.ErrorMessage)
+	GetWithPagination(ctx context.Context, countSQL string, objectType string, finalSQL string, mapper utils.RowMapperFunc, pagination *utils.Pagination, args ...any) (*utils.Pagination, *utils.ErrorMessage)
+	WithTransaction(ctx context.Context, fn TxFunc) *utils.ErrorMessage
+	WithTransactionOptions(ctx context.Context, options pgx.TxOptions, fn TxFunc) *utils.ErrorMessage
 }
 
+// TxFunc is the unit of work run by WithTransaction. Every call made through txRepo
+// shares the same transaction; returning an error rolls all of them back.
+type TxFunc func(txRepo CRUDRepository) *utils.ErrorMessage
+
+// querier is the part of the pool and of pgx.Tx used for reads.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// uniqueViolationCode is the Postgres SQLSTATE raised when a unique constraint is violated
+const uniqueViolationCode = "23505"
+
+// logComponent tags what the repositories log, its level can be set on its own at /internal/log
+var logComponent = logging.RegisterComponent("repository")
+
 type crudRepository struct {
-	db   config.DBPool
-	lock bool
+	db           config.DBPool
+	lock         bool
+	dbConfig     config.DatabaseConfig
+	queryTimeout time.Duration
+	isoLevel     pgx.TxIsoLevel
+	// tx is set on repositories handed to a TxFunc, every statement then runs inside it
+	tx pgx.Tx
 }
 
-func NewCRUDRepository(db config.DBPool) CRUDRepository {
+func NewCRUDRepository(db config.DBPool, dbConfig config.DatabaseConfig) CRUDRepository {
 	return &crudRepository{
-		db: db,
+		db:           db,
+		dbConfig:     dbConfig,
+		queryTimeout: dbConfig.QueryTimeout,
+		isoLevel:     pgx.TxIsoLevel(dbConfig.TxIsolationLevel),
 	}
 }
 
-func (crud *crudRepository) CheckAndResetDBConnection() {
+func (crud *crudRepository) CheckAndResetDBConnection(ctx context.Context) {
+	if crud.tx != nil {
+		// The transaction owns its connection, resetting the pool would not help it
+		return
+	}
 	for crud.lock {
 	}
 
 	if crud.db == nil {
 		crud.lock = true
-		logrus.Warn("DB connection is nil, resetting it")
-		crud.db = config.ConnectDB()
+		logging.ForComponent(ctx, logComponent).Warn("DB connection is nil, resetting it")
+		crud.db = config.ConnectDB(crud.dbConfig)
 		crud.lock = false
 	}
-	if err := crud.db.Ping(context.Background()); err != nil {
+	if err := crud.db.Ping(ctx); err != nil && ctx.Err() == nil {
 		crud.lock = true
-		logrus.Warn("DB connection is stale, resetting it")
-		crud.db = config.ConnectDB()
+		logging.ForComponent(ctx, logComponent).Warn("DB connection is stale, resetting it")
+		crud.db = config.ConnectDB(crud.dbConfig)
 		crud.lock = false
 	}
 }
 
-func (crud *crudRepository) Delete(query string, objectType string, args ...any) *utils.ErrorMessage {
-	logrus.Debugf("Deleting %s object in database", objectType)
+// withQueryTimeout bounds the context by the configured per-query timeout, on top of any request deadline.
+func (crud *crudRepository) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if crud.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, crud.queryTimeout)
+}
+
+// contextError reports a timed out or cancelled context as 504 and 499 respectively,
+// so callers can tell these apart from genuine database failures. It returns nil if the context is still live.
+func contextError(ctx context.Context, objectType string) *utils.ErrorMessage {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		logging.ForComponent(ctx, logComponent).Warnf("Query for %s exceeded its deadline", objectType)
+		return &utils.ErrorMessage{StatusCode: http.StatusGatewayTimeout, Message: fmt.Sprintf(constants.QUERY_TIMEOUT, objectType)}
+	case errors.Is(ctx.Err(), context.Canceled):
+		logging.ForComponent(ctx, logComponent).Warnf("Query for %s was cancelled", objectType)
+		return &utils.ErrorMessage{StatusCode: utils.StatusClientClosedRequest, Message: fmt.Sprintf(constants.QUERY_CANCELLED, objectType)}
+	}
+	return nil
+}
+
+// failure returns the context error if the context is done, and the fallback otherwise.
+func failure(ctx context.Context, objectType string, fallback *utils.ErrorMessage) *utils.ErrorMessage {
+	if ctxErr := contextError(ctx, objectType); ctxErr != nil {
+		return ctxErr
+	}
+	return fallback
+}
+
+func (crud *crudRepository) Delete(ctx context.Context, query string, objectType string, args ...any) (errMsg *utils.ErrorMessage) {
+	logging.ForComponent(ctx, logComponent).Debugf("Deleting %s object in database", objectType)
+	ctx, stmt := crud.startStatement(ctx, query, objectType)
+	defer func() { stmt.end(errMsg) }()
+	ctx, cancel := crud.withQueryTimeout(ctx)
+	defer cancel()
 	// Begin a transaction
-	tx, txErr := crud.BeginTransaction()
+	tx, txErr := crud.BeginTransaction(ctx)
 	if txErr != nil {
 		return txErr
 	}
 	// Execute the DELETE statement within the transaction
-	cmdTag, err := tx.Exec(context.Background(), query, args...)
+	cmdTag, err := tx.Exec(ctx, query, args...)
 	if err != nil {
-		logrus.Errorf("Failed to delete %s from database: %v", objectType, err)
-		return crud.RollBackTransaction(tx, objectType)
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to delete %s from database: %v", objectType, err)
+		return failure(ctx, objectType, crud.RollBackTransaction(tx, objectType))
 	}
-	logrus.Infof("Rows Affected by delete:%v", cmdTag.RowsAffected())
+	logging.ForComponent(ctx, logComponent).Infof("Rows Affected by delete:%v", cmdTag.RowsAffected())
 	if cmdTag.RowsAffected() == 0 {
-		logrus.Warnf("No %s found with the given criteria to delete", objectType)
+		logging.ForComponent(ctx, logComponent).Warnf("No %s found with the given criteria to delete", objectType)
+		// Nothing to keep, the rollback releases the transaction and its connection
+		crud.RollBackTransaction(tx, objectType)
 		return nil
 	}
 	// Commit the transaction
-	return crud.CommitTransaction(tx, objectType)
+	return crud.CommitTransaction(ctx, tx, objectType)
 }
-func (crud *crudRepository) Create(query string, objectType string, args ...any) (interface{}, *utils.ErrorMessage) {
+func (crud *crudRepository) Create(ctx context.Context, query string, objectType string, args ...any) (_ interface{}, errMsg *utils.ErrorMessage) {
 	var id interface{}
-	logrus.Debugf("Creating %s object in database", objectType)
+	logging.ForComponent(ctx, logComponent).Debugf("Creating %s object in database", objectType)
+	ctx, stmt := crud.startStatement(ctx, query, objectType)
+	defer func() { stmt.end(errMsg) }()
+	ctx, cancel := crud.withQueryTimeout(ctx)
+	defer cancel()
 	// Begin a transaction
-	tx, txErr := crud.BeginTransaction()
+	tx, txErr := crud.BeginTransaction(ctx)
 	if txErr != nil {
 		return -1, txErr
 	}
-	if err := tx.QueryRow(context.Background(), query, args...).Scan(&id); err != nil {
-		logrus.Errorf("Failed to create %s in database: %v", objectType, err)
-		logrus.Errorf("Rollign back Create transaction for %s", objectType)
+	if err := tx.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to create %s in database: %v", objectType, err)
+		logging.ForComponent(ctx, logComponent).Errorf("Rollign back Create transaction for %s", objectType)
 		crud.RollBackTransaction(tx, objectType)
-		return -1, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError,
-			Message: fmt.Sprintf(constants.FAILED_TO_CREATE_OBJ, objectType)}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return -1, &utils.ErrorMessage{StatusCode: http.StatusConflict,
+				Message: fmt.Sprintf(constants.DUPLICATE_OBJ, objectType)}
+		}
+		return -1, failure(ctx, objectType, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError,
+			Message: fmt.Sprintf(constants.FAILED_TO_CREATE_OBJ, objectType)})
 	}
 
-	cErr := crud.CommitTransaction(tx, objectType)
+	cErr := crud.CommitTransaction(ctx, tx, objectType)
 	if cErr != nil {
-		return -1, crud.RollBackTransaction(tx, objectType)
+		return -1, failure(ctx, objectType, crud.RollBackTransaction(tx, objectType))
 	}
 	return id, nil
 }
-func (crud *crudRepository) BeginTransaction() (pgx.Tx, *utils.ErrorMessage) {
-	crud.CheckAndResetDBConnection()
-	tx, err := crud.db.Begin(context.Background())
+
+// BeginTransaction starts a transaction on the pool, or a savepoint when called inside WithTransaction.
+func (crud *crudRepository) BeginTransaction(ctx context.Context) (pgx.Tx, *utils.ErrorMessage) {
+	var tx pgx.Tx
+	var err error
+	if crud.tx != nil {
+		tx, err = crud.tx.Begin(ctx)
+	} else {
+		crud.CheckAndResetDBConnection(ctx)
+		tx, err = crud.db.Begin(ctx)
+	}
 	if err != nil {
-		logrus.Errorf("Failed to begin transaction: %v", err)
-		return nil, &utils.ErrorMessage{
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to begin transaction: %v", err)
+		return nil, failure(ctx, constants.UNDEFINED, &utils.ErrorMessage{
 			StatusCode: http.StatusInternalServerError,
 			Message:    constants.FAILED_BEGIN_TRANSACTION,
-		}
+		})
 	}
 	return tx, nil
 }
 
-func (crud *crudRepository) CommitTransaction(tx pgx.Tx, objectType string) *utils.ErrorMessage {
-	if err := tx.Commit(context.Background()); err != nil {
-		logrus.Errorf("Failed to commit transaction for %s update: %v", objectType, err)
-		return &utils.ErrorMessage{
+func (crud *crudRepository) CommitTransaction(ctx context.Context, tx pgx.Tx, objectType string) *utils.ErrorMessage {
+	if err := tx.Commit(ctx); err != nil {
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to commit transaction for %s update: %v", objectType, err)
+		return failure(ctx, objectType, &utils.ErrorMessage{
 			StatusCode: http.StatusInternalServerError,
 			Message:    fmt.Sprintf(constants.COMMIT_FAILED, objectType),
-		}
+		})
 	}
 	return nil
 }
 
+// RollBackTransaction deliberately ignores the request context: a cancelled request
+// still has to release its transaction and connection.
 func (crud *crudRepository) RollBackTransaction(tx pgx.Tx, objectType string) *utils.ErrorMessage {
 	if rollbackErr := tx.Rollback(context.Background()); rollbackErr != nil {
-		logrus.Errorf("Failed to rollback transaction for %s update: %v", objectType, rollbackErr)
+		logging.Component(logComponent).Errorf("Failed to rollback transaction for %s update: %v", objectType, rollbackErr)
 	}
 
 	return &utils.ErrorMessage{
@@ -133,57 +223,74 @@ func (crud *crudRepository) RollBackTransaction(tx pgx.Tx, objectType string) *u
 	}
 }
 
-func (crud *crudRepository) Update(query string, objectType string, args ...any) *utils.ErrorMessage {
-	logrus.Debugf("Updating %s object in database", objectType)
+func (crud *crudRepository) Update(ctx context.Context, query string, objectType string, args ...any) (errMsg *utils.ErrorMessage) {
+	logging.ForComponent(ctx, logComponent).Debugf("Updating %s object in database", objectType)
+	ctx, stmt := crud.startStatement(ctx, query, objectType)
+	defer func() { stmt.end(errMsg) }()
+	ctx, cancel := crud.withQueryTimeout(ctx)
+	defer cancel()
 	// Begin a transaction
-	tx, txerr := crud.BeginTransaction()
+	tx, txerr := crud.BeginTransaction(ctx)
 	if txerr != nil {
 		return txerr
 	}
 	// Execute the UPDATE statement within the transaction
-	cmdTag, err := tx.Exec(context.Background(), query, args...)
+	cmdTag, err := tx.Exec(ctx, query, args...)
 	if err != nil {
-		logrus.Errorf("Failed to update %s in database: %v", objectType, err)
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to update %s in database: %v", objectType, err)
 		// If an error occurs, rollback the transaction
-		return crud.RollBackTransaction(tx, objectType)
+		rollbackErr := crud.RollBackTransaction(tx, objectType)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return &utils.ErrorMessage{StatusCode: http.StatusConflict,
+				Message: fmt.Sprintf(constants.DUPLICATE_OBJ, objectType)}
+		}
+		return failure(ctx, objectType, rollbackErr)
 	}
 
 	// Check if any row was actually updated
 	if cmdTag.RowsAffected() == 0 {
 		// No rows affected, might want to handle this as an error or just a no-op
-		logrus.Warnf("No %s found with the given criteria to update", objectType)
+		logging.ForComponent(ctx, logComponent).Warnf("No %s found with the given criteria to update", objectType)
+		// Nothing to keep, the rollback releases the transaction and its connection
+		crud.RollBackTransaction(tx, objectType)
 		return &utils.ErrorMessage{
 			StatusCode: http.StatusNotFound,
 			Message:    fmt.Sprintf(constants.NO_ROWS_AFFECTED, objectType),
 		}
 	}
-	return crud.CommitTransaction(tx, objectType)
+	return crud.CommitTransaction(ctx, tx, objectType)
 }
 
-func (crud *crudRepository) GetWithPagination(countSQL string, objectType string, finalSQL string, mapper utils.RowMapperFunc, pagination *utils.Pagination, args ...any) (*utils.Pagination, *utils.ErrorMessage) {
-	crud.CheckAndResetDBConnection()
-	ctx := context.Background()
-	rows, err := crud.db.Query(ctx, finalSQL, args...)
+func (crud *crudRepository) GetWithPagination(ctx context.Context, countSQL string, objectType string, finalSQL string, mapper utils.RowMapperFunc, pagination *utils.Pagination, args ...any) (_ *utils.Pagination, errMsg *utils.ErrorMessage) {
+	ctx, stmt := crud.startStatement(ctx, finalSQL, objectType)
+	defer func() { stmt.end(errMsg) }()
+	ctx, cancel := crud.withQueryTimeout(ctx)
+	defer cancel()
+	crud.CheckAndResetDBConnection(ctx)
+	rows, err := crud.querier().Query(ctx, finalSQL, args...)
 	if err != nil {
-		logrus.Errorf("Failed to execute query: %v for %s", err, objectType)
-		return nil, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: "Failed to execute query"}
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to execute query: %v for %s", err, objectType)
+		return nil, failure(ctx, objectType, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: "Failed to execute query"})
 	}
 	defer rows.Close()
 	var results []interface{}
 	for rows.Next() {
 		item, err := mapper(rows)
 		if err != nil {
-			logrus.Errorf("Failed to map row: %v", err)
-			return nil, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: constants.FAILED_SCAN}
+			logging.ForComponent(ctx, logComponent).Errorf("Failed to map row: %v", err)
+			return nil, failure(ctx, objectType, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: constants.FAILED_SCAN})
 		}
 		results = append(results, item)
 	}
+	if err := rows.Err(); err != nil {
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to read rows: %v for %s", err, objectType)
+		return nil, failure(ctx, objectType, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: constants.FAILED_SCAN})
+	}
 
-	var totalRows int64
-	err = crud.db.QueryRow(ctx, countSQL).Scan(&totalRows)
-	if err != nil {
-		logrus.Errorf("Failed to count total rows: %v", err)
-		return nil, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: "Failed to count total rows"}
+	totalRows, countErr := crud.count(ctx, countSQL, objectType, args...)
+	if countErr != nil {
+		return nil, countErr
 	}
 
 	pagination.TotalRows = totalRows
@@ -192,13 +299,27 @@ func (crud *crudRepository) GetWithPagination(countSQL string, objectType string
 	return pagination, nil
 }
 
-func (crud *crudRepository) Get(query string, objectType string, mapper utils.RowMapperFunc, args ...any) ([]interface{}, *utils.ErrorMessage) {
-	crud.CheckAndResetDBConnection()
-	ctx := context.Background()
-	rows, err := crud.db.Query(ctx, query, args...)
+// count runs the count query of GetWithPagination in a span of its own.
+func (crud *crudRepository) count(ctx context.Context, countSQL string, objectType string, args ...any) (totalRows int64, errMsg *utils.ErrorMessage) {
+	ctx, stmt := crud.startStatement(ctx, countSQL, objectType)
+	defer func() { stmt.end(errMsg) }()
+	if err := crud.querier().QueryRow(ctx, countSQL, args...).Scan(&totalRows); err != nil {
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to count total rows: %v", err)
+		return 0, failure(ctx, objectType, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: "Failed to count total rows"})
+	}
+	return totalRows, nil
+}
+
+func (crud *crudRepository) Get(ctx context.Context, query string, objectType string, mapper utils.RowMapperFunc, args ...any) (_ []interface{}, errMsg *utils.ErrorMessage) {
+	ctx, stmt := crud.startStatement(ctx, query, objectType)
+	defer func() { stmt.end(errMsg) }()
+	ctx, cancel := crud.withQueryTimeout(ctx)
+	defer cancel()
+	crud.CheckAndResetDBConnection(ctx)
+	rows, err := crud.querier().Query(ctx, query, args...)
 	if err != nil {
-		logrus.Errorf("Failed to execute query: %v for %v", err, objectType)
-		return nil, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: "Failed to execute query"}
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to execute query: %v for %v", err, objectType)
+		return nil, failure(ctx, objectType, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: "Failed to execute query"})
 	}
 	defer rows.Close()
 
@@ -206,25 +327,92 @@ func (crud *crudRepository) Get(query string, objectType string, mapper utils.Ro
 	for rows.Next() {
 		item, err := mapper(rows)
 		if err != nil {
-			logrus.Errorf("Failed to map row: %v", err)
-			return nil, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: "Failed to scan row"}
+			logging.ForComponent(ctx, logComponent).Errorf("Failed to map row: %v", err)
+			return nil, failure(ctx, objectType, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: "Failed to scan row"})
 		}
 		results = append(results, item)
 	}
+	if err := rows.Err(); err != nil {
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to read rows: %v for %v", err, objectType)
+		return nil, failure(ctx, objectType, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: "Failed to scan row"})
+	}
 	return results, nil
 }
 
-func (crud *crudRepository) GetOne(query string, objectType string, mapper utils.RowMapperFunc, args ...any) (interface{}, *utils.ErrorMessage) {
-	crud.CheckAndResetDBConnection()
-	row := crud.db.QueryRow(context.Background(), query, args...)
+func (crud *crudRepository) GetOne(ctx context.Context, query string, objectType string, mapper utils.RowMapperFunc, args ...any) (_ interface{}, errMsg *utils.ErrorMessage) {
+	ctx, stmt := crud.startStatement(ctx, query, objectType)
+	defer func() { stmt.end(errMsg) }()
+	ctx, cancel := crud.withQueryTimeout(ctx)
+	defer cancel()
+	crud.CheckAndResetDBConnection(ctx)
+	row := crud.querier().QueryRow(ctx, query, args...)
 	item, err := mapper(row)
 	if err != nil {
-		logrus.Debugf("Adding Query : %v \n", query)
-		logrus.Errorf("Failed to execute query or map row: %v", err)
+		logging.ForComponent(ctx, logComponent).Debugf("Adding Query : %v", tracing.SanitizeSQL(query))
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to execute query or map row: %v", err)
 		if err == pgx.ErrNoRows {
 			return nil, &utils.ErrorMessage{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("No %s found with the given criteria", objectType)}
 		}
-		return nil, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: fmt.Sprintf(constants.FAILED_SCAN)}
+		return nil, failure(ctx, objectType, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: fmt.Sprintf(constants.FAILED_SCAN)})
 	}
 	return item, nil
 }
+
+// querier returns the transaction when running inside WithTransaction and the pool otherwise.
+func (crud *crudRepository) querier() querier {
+	if crud.tx != nil {
+		return crud.tx
+	}
+	return crud.db
+}
+
+// WithTransaction runs fn in a transaction using the default isolation level from DB_TX_ISOLATION_LEVEL.
+func (crud *crudRepository) WithTransaction(ctx context.Context, fn TxFunc) *utils.ErrorMessage {
+	return crud.WithTransactionOptions(ctx, pgx.TxOptions{IsoLevel: crud.isoLevel}, fn)
+}
+
+// WithTransactionOptions runs fn in a transaction and commits it if fn succeeds. The transaction is
+// rolled back if fn returns an error or panics, the panic is then re-raised. Called on a txRepo it
+// nests through a savepoint instead, options are ignored as the outer transaction decides them.
+func (crud *crudRepository) WithTransactionOptions(ctx context.Context, options pgx.TxOptions, fn TxFunc) (errMsg *utils.ErrorMessage) {
+	const objectType = "transaction"
+	ctx, stmt := crud.start(ctx, "TRANSACTION", objectType, attribute.Bool("db.transaction.nested", crud.tx != nil))
+	defer func() { stmt.end(errMsg) }()
+	var tx pgx.Tx
+	var err error
+	if crud.tx != nil {
+		tx, err = crud.tx.Begin(ctx)
+	} else {
+		crud.CheckAndResetDBConnection(ctx)
+		tx, err = crud.db.BeginTx(ctx, options)
+	}
+	if err != nil {
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to begin transaction: %v", err)
+		return failure(ctx, objectType, &utils.ErrorMessage{
+			StatusCode: http.StatusInternalServerError,
+			Message:    constants.FAILED_BEGIN_TRANSACTION,
+		})
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			logging.ForComponent(ctx, logComponent).Errorf("Rolling back transaction after panic: %v", p)
+			crud.RollBackTransaction(tx, objectType)
+			panic(p)
+		}
+	}()
+
+	txRepo := &crudRepository{
+		db:           crud.db,
+		dbConfig:     crud.dbConfig,
+		queryTimeout: crud.queryTimeout,
+		isoLevel:     crud.isoLevel,
+		tx:           tx,
+	}
+	if fnErr := fn(txRepo); fnErr != nil {
+		logging.ForComponent(ctx, logComponent).Warnf("Rolling back transaction: %v", fnErr.Message)
+		crud.RollBackTransaction(tx, objectType)
+		return fnErr
+	}
+	return crud.CommitTransaction(ctx, tx, objectType)
+}
@@ -1,13 +1,23 @@
 package Repository
 
 import (
+	"context"
 	"errors"
+	"net/http"
 	"starter/internal/app/utils"
+	"starter/internal/config"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/pashagolub/pgxmock/v3"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 type testStruct struct {
@@ -26,12 +36,12 @@ var testMapper = func(row pgx.Row) (interface{}, error) {
 func Test_Get_Success(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectQuery(`SELECT * `).
 		WithArgs(1).
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
-	_, err := crud.Get(`SELECT * `, "test", testMapper, 1)
+	_, err := crud.Get(context.Background(), `SELECT * `, "test", testMapper, 1)
 	assert.Nil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -41,16 +51,17 @@ func Test_Get_Success(t *testing.T) {
 func Test_Get_Paginated_Success(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectQuery(`SELECT * `).
 		WithArgs(1).
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
 
 	dbMock.ExpectQuery(`SELECT COUNT`).
+		WithArgs(1).
 		WillReturnRows(pgxmock.NewRows([]string{"count"}).
 			AddRow(int64(1)))
-	_, err := crud.GetWithPagination(`SELECT COUNT`, "test", `SELECT * `, testMapper, &utils.Pagination{}, 1)
+	_, err := crud.GetWithPagination(context.Background(), `SELECT COUNT`, "test", `SELECT * `, testMapper, &utils.Pagination{}, 1)
 	assert.Nil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -60,12 +71,12 @@ func Test_GetOne_Success(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
 
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectQuery(`SELECT * `).
 		WithArgs(1).
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
-	_, err := crud.GetOne(`SELECT * `, "test", testMapper, 1)
+	_, err := crud.GetOne(context.Background(), `SELECT * `, "test", testMapper, 1)
 	assert.Nil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -75,14 +86,14 @@ func Test_GetOne_Success(t *testing.T) {
 func Test_CRUDRepository_Delete(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectExec(`DELETE`).
 		WithArgs(1).WillReturnResult(pgxmock.NewResult("DELETE", 1))
 	dbMock.ExpectCommit()
 
-	err := crud.Delete(`DELETE FROM "public"."users" WHERE "userEmailId" = $1`, "test", 1)
+	err := crud.Delete(context.Background(), `DELETE FROM "public"."users" WHERE "userEmailId" = $1`, "test", 1)
 	if err != nil {
 		t.Error(err)
 	}
@@ -94,13 +105,13 @@ func Test_CRUDRepository_Delete(t *testing.T) {
 func Test_CRUDRepository_Create(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectQuery(`INSERT INTO`).
 		WithArgs(1).WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
 	dbMock.ExpectCommit()
-	_, err := crud.Create(`INSERT INTO`, "", 1)
+	_, err := crud.Create(context.Background(), `INSERT INTO`, "", 1)
 	assert.Nil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -110,13 +121,13 @@ func Test_CRUDRepository_Create(t *testing.T) {
 func Test_CRUDRepository_Update(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectExec(`Update`).
 		WithArgs(1).WillReturnResult(pgxmock.NewResult("Update", 1))
 	dbMock.ExpectCommit()
-	err := crud.Update(`Update`, "", 1)
+	err := crud.Update(context.Background(), `Update`, "", 1)
 	assert.Nil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -125,13 +136,14 @@ func Test_CRUDRepository_Update(t *testing.T) {
 func Test_CRUDRepository_Delete2(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectExec(`DELETE`).
 		WithArgs(1).WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	dbMock.ExpectRollback()
 
-	err := crud.Delete(`DELETE FROM "public"."users" WHERE "userEmailId" = $1`, "test", 1)
+	err := crud.Delete(context.Background(), `DELETE FROM "public"."users" WHERE "userEmailId" = $1`, "test", 1)
 	if err != nil {
 		t.Error(err)
 	}
@@ -143,14 +155,14 @@ func Test_CRUDRepository_Delete2(t *testing.T) {
 func Test_CRUDRepository_Delete_Fail(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectExec(`DELETE`).
 		WithArgs(1).WillReturnError(errors.New("some error"))
 	dbMock.ExpectRollback()
 
-	err := crud.Delete(`DELETE FROM "public"."users" WHERE "userEmailId" = $1`, "test", 1)
+	err := crud.Delete(context.Background(), `DELETE FROM "public"."users" WHERE "userEmailId" = $1`, "test", 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -160,10 +172,10 @@ func Test_CRUDRepository_Delete_Fail(t *testing.T) {
 func Test_CRUDRepository_Delete_Fail2(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin().WillReturnError(errors.New("some error"))
-	err := crud.Delete(`DELETE FROM "public"."users" WHERE "userEmailId" = $1`, "test", 1)
+	err := crud.Delete(context.Background(), `DELETE FROM "public"."users" WHERE "userEmailId" = $1`, "test", 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -173,14 +185,14 @@ func Test_CRUDRepository_Delete_Fail2(t *testing.T) {
 func Test_CRUDRepository_Delete_Fail3(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectExec(`DELETE`).
 		WithArgs(1).WillReturnResult(pgxmock.NewResult("DELETE", 1))
 	dbMock.ExpectCommit().WillReturnError(errors.New("some error"))
 
-	err := crud.Delete(`DELETE FROM "public"."users" WHERE "userEmailId" = $1`, "test", 1)
+	err := crud.Delete(context.Background(), `DELETE FROM "public"."users" WHERE "userEmailId" = $1`, "test", 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -190,14 +202,14 @@ func Test_CRUDRepository_Delete_Fail3(t *testing.T) {
 func Test_CRUDRepository_Delete_Fail5(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectExec(`DELETE`).
 		WithArgs(1).WillReturnError(errors.New("some error"))
 	dbMock.ExpectRollback().WillReturnError(errors.New("some error"))
 
-	err := crud.Delete(`DELETE FROM "public"."users" WHERE "userEmailId" = $1`, "test", 1)
+	err := crud.Delete(context.Background(), `DELETE FROM "public"."users" WHERE "userEmailId" = $1`, "test", 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -207,10 +219,10 @@ func Test_CRUDRepository_Delete_Fail5(t *testing.T) {
 func Test_CRUDRepository_Create_Fail1(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin().WillReturnError(errors.New("some error"))
-	_, err := crud.Create(`INSERT INTO`, "", nil)
+	_, err := crud.Create(context.Background(), `INSERT INTO`, "", nil)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -220,14 +232,14 @@ func Test_CRUDRepository_Create_Fail1(t *testing.T) {
 func Test_CRUDRepository_Create_Fail2(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectQuery(`INSERT INTO`).
 		WithArgs(1).
 		WillReturnError(errors.New("some error"))
 	dbMock.ExpectRollback()
-	_, err := crud.Create(`INSERT INTO`, "", 1)
+	_, err := crud.Create(context.Background(), `INSERT INTO`, "", 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -237,14 +249,14 @@ func Test_CRUDRepository_Create_Fail2(t *testing.T) {
 func Test_CRUDRepository_Create_Fail3(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectQuery(`INSERT INTO`).
 		WithArgs(1).WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
 	dbMock.ExpectCommit().WillReturnError(errors.New("some error"))
 	dbMock.ExpectRollback()
-	_, err := crud.Create(`INSERT INTO`, "", 1)
+	_, err := crud.Create(context.Background(), `INSERT INTO`, "", 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -254,15 +266,68 @@ func Test_CRUDRepository_Create_Fail3(t *testing.T) {
 func Test_CRUDRepository_Create_Fail4(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectQuery(`INSERT INTO`).
 		WithArgs(1).WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
 	dbMock.ExpectCommit().WillReturnError(errors.New("some error"))
 	dbMock.ExpectRollback().WillReturnError(errors.New("some error"))
-	_, err := crud.Create(`INSERT INTO`, "", 1)
+	_, err := crud.Create(context.Background(), `INSERT INTO`, "", 1)
+	assert.NotNil(t, err)
+	if e := dbMock.ExpectationsWereMet(); e != nil {
+		t.Errorf("there were unfulfilled expectations: %s", e)
+	}
+}
+
+func Test_CRUDRepository_Create_Conflict(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
+	defer dbMock.Close()
+	dbMock.ExpectBegin()
+	dbMock.ExpectQuery(`INSERT INTO`).
+		WithArgs(1).
+		WillReturnError(&pgconn.PgError{Code: uniqueViolationCode})
+	dbMock.ExpectRollback()
+	_, err := crud.Create(context.Background(), `INSERT INTO`, "", 1)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusConflict, err.StatusCode)
+	if e := dbMock.ExpectationsWereMet(); e != nil {
+		t.Errorf("there were unfulfilled expectations: %s", e)
+	}
+}
+
+func Test_CRUDRepository_Update_NotFound(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
+	defer dbMock.Close()
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(`Update`).
+		WithArgs(1).WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	dbMock.ExpectRollback()
+	err := crud.Update(context.Background(), `Update`, "", 1)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+	if e := dbMock.ExpectationsWereMet(); e != nil {
+		t.Errorf("there were unfulfilled expectations: %s", e)
+	}
+}
+
+func Test_CRUDRepository_Update_Conflict(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
+	defer dbMock.Close()
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(`Update`).
+		WithArgs(1).
+		WillReturnError(&pgconn.PgError{Code: uniqueViolationCode})
+	dbMock.ExpectRollback()
+	err := crud.Update(context.Background(), `Update`, "", 1)
 	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusConflict, err.StatusCode)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
 	}
@@ -271,10 +336,10 @@ func Test_CRUDRepository_Create_Fail4(t *testing.T) {
 func Test_CRUDRepository_Update_Fail1(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin().WillReturnError(errors.New("some error"))
-	err := crud.Update(`Update`, "", nil)
+	err := crud.Update(context.Background(), `Update`, "", nil)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -284,14 +349,14 @@ func Test_CRUDRepository_Update_Fail1(t *testing.T) {
 func Test_CRUDRepository_Update_Fail2(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectExec(`Update`).
 		WithArgs(1).
 		WillReturnError(errors.New("some error"))
 	dbMock.ExpectRollback()
-	err := crud.Update(`Update`, "", 1)
+	err := crud.Update(context.Background(), `Update`, "", 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -301,11 +366,11 @@ func Test_CRUDRepository_Update_Fail2(t *testing.T) {
 func Test_CRUDRepository_Update_Fail3(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectExec(`Update`).WithArgs(1).WillReturnResult(pgxmock.NewResult("Update", 0))
-	err := crud.Update(`Update`, "", 1)
+	err := crud.Update(context.Background(), `Update`, "", 1)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
 	}
@@ -315,12 +380,12 @@ func Test_CRUDRepository_Update_Fail3(t *testing.T) {
 func Test_CRUDRepository_Update_Fail4(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectExec(`Update`).WithArgs(1).WillReturnResult(pgxmock.NewResult("Update", 1))
 	dbMock.ExpectCommit().WillReturnError(errors.New("some error"))
-	err := crud.Update(`Update`, "", 1)
+	err := crud.Update(context.Background(), `Update`, "", 1)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
 	}
@@ -329,14 +394,14 @@ func Test_CRUDRepository_Update_Fail4(t *testing.T) {
 func Test_CRUDRepository_Update_Fail5(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectBegin()
 	dbMock.ExpectExec(`Update`).
 		WithArgs(1).
 		WillReturnError(errors.New("some error"))
 	dbMock.ExpectRollback().WillReturnError(errors.New("some error"))
-	err := crud.Update(`Update`, "", 1)
+	err := crud.Update(context.Background(), `Update`, "", 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -346,12 +411,12 @@ func Test_CRUDRepository_Update_Fail5(t *testing.T) {
 func Test_GetOne_Fail1(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectQuery(`SELECT * `).
 		WithArgs(1).
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("a"))
-	_, err := crud.GetOne(`SELECT * `, "test", testMapper, 1)
+	_, err := crud.GetOne(context.Background(), `SELECT * `, "test", testMapper, 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -361,12 +426,12 @@ func Test_GetOne_Fail1(t *testing.T) {
 func Test_GetOne_Fail2(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectQuery(`SELECT * `).
 		WithArgs(1).
 		WillReturnRows(pgxmock.NewRows([]string{"id"}))
-	_, err := crud.GetOne(`SELECT * `, "test", testMapper, 1)
+	_, err := crud.GetOne(context.Background(), `SELECT * `, "test", testMapper, 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -376,12 +441,12 @@ func Test_GetOne_Fail2(t *testing.T) {
 func Test_Get_Paginated_Failure1(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectQuery(`SELECT * `).
 		WithArgs(1).
 		WillReturnError(errors.New("some error"))
-	_, err := crud.GetWithPagination(`SELECT COUNT`, "test", `SELECT * `, testMapper, &utils.Pagination{}, 1)
+	_, err := crud.GetWithPagination(context.Background(), `SELECT COUNT`, "test", `SELECT * `, testMapper, &utils.Pagination{}, 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -391,12 +456,12 @@ func Test_Get_Paginated_Failure1(t *testing.T) {
 func Test_Get_Paginated_Failure2(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectQuery(`SELECT * `).
 		WithArgs(1).
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("a"))
-	_, err := crud.GetWithPagination(`SELECT COUNT`, "test", `SELECT * `, testMapper, &utils.Pagination{}, 1)
+	_, err := crud.GetWithPagination(context.Background(), `SELECT COUNT`, "test", `SELECT * `, testMapper, &utils.Pagination{}, 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -406,14 +471,14 @@ func Test_Get_Paginated_Failure2(t *testing.T) {
 func Test_Get_Paginated_Failure3(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectQuery(`SELECT * `).
 		WithArgs(1).
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
 
-	dbMock.ExpectQuery(`SELECT COUNT`).WillReturnError(errors.New("some error"))
-	_, err := crud.GetWithPagination(`SELECT COUNT`, "test", `SELECT * `, testMapper, &utils.Pagination{}, 1)
+	dbMock.ExpectQuery(`SELECT COUNT`).WithArgs(1).WillReturnError(errors.New("some error"))
+	_, err := crud.GetWithPagination(context.Background(), `SELECT COUNT`, "test", `SELECT * `, testMapper, &utils.Pagination{}, 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -423,12 +488,12 @@ func Test_Get_Paginated_Failure3(t *testing.T) {
 func Test_Get_Fail1(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectQuery(`SELECT * `).
 		WithArgs(1).
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("a"))
-	_, err := crud.Get(`SELECT * `, "test", testMapper, 1)
+	_, err := crud.Get(context.Background(), `SELECT * `, "test", testMapper, 1)
 	assert.NotNil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
@@ -438,14 +503,194 @@ func Test_Get_Fail1(t *testing.T) {
 func Test_Get_Fail2(t *testing.T) {
 	dbMock, _ := pgxmock.NewPool()
 	dbMock.ExpectPing().WillReturnError(nil)
-	crud := NewCRUDRepository(dbMock)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
 	defer dbMock.Close()
 	dbMock.ExpectQuery(`SELECT * `).
 		WithArgs(1).
 		WillReturnError(errors.New("some error"))
-	_, err := crud.Get(`SELECT * `, "test", testMapper, 1)
+	_, err := crud.Get(context.Background(), `SELECT * `, "test", testMapper, 1)
+	assert.NotNil(t, err)
+	if e := dbMock.ExpectationsWereMet(); e != nil {
+		t.Errorf("there were unfulfilled expectations: %s", e)
+	}
+}
+
+func Test_Get_Cancelled(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
+	defer dbMock.Close()
+	dbMock.ExpectQuery(`SELECT * `).
+		WithArgs(1).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1)).
+		WillDelayFor(time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err := crud.Get(ctx, `SELECT * `, "test", testMapper, 1)
+	assert.NotNil(t, err)
+	assert.Equal(t, utils.StatusClientClosedRequest, err.StatusCode)
+}
+
+func Test_GetOne_Timeout(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
+	defer dbMock.Close()
+	dbMock.ExpectQuery(`SELECT * `).
+		WithArgs(1).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1)).
+		WillDelayFor(time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := crud.GetOne(ctx, `SELECT * `, "test", testMapper, 1)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusGatewayTimeout, err.StatusCode)
+}
+
+func Test_WithTransaction_Commit(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
+	defer dbMock.Close()
+	dbMock.ExpectBeginTx(pgx.TxOptions{IsoLevel: pgx.ReadCommitted})
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(`Update`).
+		WithArgs(1).WillReturnResult(pgxmock.NewResult("Update", 1))
+	dbMock.ExpectCommit()
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(`DELETE`).
+		WithArgs(1).WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	dbMock.ExpectCommit()
+	dbMock.ExpectCommit()
+	err := crud.WithTransaction(context.Background(), func(txRepo CRUDRepository) *utils.ErrorMessage {
+		if err := txRepo.Update(context.Background(), `Update`, "", 1); err != nil {
+			return err
+		}
+		return txRepo.Delete(context.Background(), `DELETE`, "", 1)
+	})
+	assert.Nil(t, err)
+	if e := dbMock.ExpectationsWereMet(); e != nil {
+		t.Errorf("there were unfulfilled expectations: %s", e)
+	}
+}
+
+func Test_WithTransaction_Rollback(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
+	defer dbMock.Close()
+	dbMock.ExpectBeginTx(pgx.TxOptions{IsoLevel: pgx.ReadCommitted})
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(`Update`).
+		WithArgs(1).WillReturnResult(pgxmock.NewResult("Update", 1))
+	dbMock.ExpectCommit()
+	dbMock.ExpectRollback()
+	err := crud.WithTransaction(context.Background(), func(txRepo CRUDRepository) *utils.ErrorMessage {
+		if err := txRepo.Update(context.Background(), `Update`, "", 1); err != nil {
+			return err
+		}
+		return &utils.ErrorMessage{StatusCode: http.StatusNotFound, Message: "not found"}
+	})
 	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+	if e := dbMock.ExpectationsWereMet(); e != nil {
+		t.Errorf("there were unfulfilled expectations: %s", e)
+	}
+}
+
+func Test_WithTransaction_Panic(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
+	defer dbMock.Close()
+	dbMock.ExpectBeginTx(pgx.TxOptions{IsoLevel: pgx.ReadCommitted})
+	dbMock.ExpectRollback()
+	assert.Panics(t, func() {
+		_ = crud.WithTransaction(context.Background(), func(txRepo CRUDRepository) *utils.ErrorMessage {
+			panic("boom")
+		})
+	})
+	if e := dbMock.ExpectationsWereMet(); e != nil {
+		t.Errorf("there were unfulfilled expectations: %s", e)
+	}
+}
+
+func Test_WithTransaction_Nested(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
+	defer dbMock.Close()
+	dbMock.ExpectBeginTx(pgx.TxOptions{IsoLevel: pgx.ReadCommitted})
+	dbMock.ExpectBegin()
+	dbMock.ExpectRollback()
+	dbMock.ExpectCommit()
+	err := crud.WithTransaction(context.Background(), func(txRepo CRUDRepository) *utils.ErrorMessage {
+		nestedErr := txRepo.WithTransaction(context.Background(), func(CRUDRepository) *utils.ErrorMessage {
+			return &utils.ErrorMessage{StatusCode: http.StatusConflict, Message: "conflict"}
+		})
+		assert.NotNil(t, nestedErr)
+		return nil
+	})
+	assert.Nil(t, err)
 	if e := dbMock.ExpectationsWereMet(); e != nil {
 		t.Errorf("there were unfulfilled expectations: %s", e)
 	}
 }
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]string {
+	attributes := map[attribute.Key]string{}
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value.Emit()
+	}
+	return attributes
+}
+
+func Test_Get_Traced(t *testing.T) {
+	recorder := recordSpans(t)
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
+	defer dbMock.Close()
+	dbMock.ExpectQuery(`SELECT id FROM test`).
+		WithArgs("secret").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+	_, err := crud.Get(context.Background(), "SELECT id FROM test\n\tWHERE name = $1 LIMIT 10", "test", testMapper, "secret")
+	assert.Nil(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "SELECT test", spans[0].Name())
+	attributes := spanAttributes(spans[0])
+	assert.Equal(t, "SELECT id FROM test WHERE name = $1 LIMIT ?", attributes["db.query.text"])
+	assert.Equal(t, "test", attributes["app.object_type"])
+	assert.Equal(t, "postgresql", attributes["db.system"])
+	for _, value := range attributes {
+		assert.NotContains(t, value, "secret", "arguments are never recorded")
+	}
+}
+
+func Test_Update_Traced_Failure(t *testing.T) {
+	recorder := recordSpans(t)
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	crud := NewCRUDRepository(dbMock, config.Defaults().Database)
+	defer dbMock.Close()
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(`UPDATE test`).WillReturnError(errors.New("boom"))
+	dbMock.ExpectRollback()
+	err := crud.Update(context.Background(), `UPDATE test SET id = 2`, "test")
+	assert.NotNil(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "UPDATE test", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
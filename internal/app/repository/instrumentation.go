@@ -0,0 +1,60 @@
+package Repository
+
+import (
+	"context"
+	"net/http"
+	"starter/internal/app/metrics"
+	"starter/internal/app/tracing"
+	"starter/internal/app/utils"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// objectTypeKey records the objectType the repositories pass along with each query.
+const objectTypeKey = attribute.Key("app.object_type")
+
+// statement is one traced and measured database call, see startStatement.
+type statement struct {
+	span       trace.Span
+	objectType string
+	operation  string
+	start      time.Time
+}
+
+// startStatement opens a client span for one statement, named like "SELECT user", and starts
+// timing it. Only the sanitized SQL is recorded, never the arguments.
+func (crud *crudRepository) startStatement(ctx context.Context, query string, objectType string) (context.Context, *statement) {
+	sanitized := tracing.SanitizeSQL(query)
+	operation, _, _ := strings.Cut(sanitized, " ")
+	return crud.start(ctx, strings.ToUpper(operation), objectType, semconv.DBQueryText(sanitized))
+}
+
+func (crud *crudRepository) start(ctx context.Context, operation string, objectType string, attributes ...attribute.KeyValue) (context.Context, *statement) {
+	attributes = append(attributes,
+		semconv.DBSystemPostgreSQL,
+		semconv.DBNamespace(crud.dbConfig.Name),
+		semconv.DBOperationName(operation),
+		objectTypeKey.String(objectType),
+	)
+	ctx, span := tracing.Tracer().Start(ctx, operation+" "+objectType,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+	return ctx, &statement{span: span, objectType: objectType, operation: operation, start: time.Now()}
+}
+
+// end closes the span and records the duration. Lookups finding nothing are an answer, not a failure.
+func (s *statement) end(errMsg *utils.ErrorMessage) {
+	failed := errMsg != nil && errMsg.StatusCode != http.StatusNotFound
+	if errMsg != nil {
+		s.span.SetAttributes(attribute.Int("app.status_code", errMsg.StatusCode))
+	}
+	if failed {
+		s.span.SetStatus(codes.Error, errMsg.Message)
+	}
+	s.span.End()
+	metrics.ObserveQuery(s.objectType, s.operation, time.Since(s.start), failed)
+}
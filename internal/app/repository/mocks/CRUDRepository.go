@@ -3,9 +3,13 @@
 package mocks
 
 import (
-	pgx "github.com/jackc/pgx/v5"
+	context "context"
+	Repository "starter/internal/app/repository"
+
 	mock "github.com/stretchr/testify/mock"
 
+	pgx "github.com/jackc/pgx/v5"
+
 	utils "starter/internal/app/utils"
 )
 
@@ -14,9 +18,9 @@ type CRUDRepository struct {
 	mock.Mock
 }
 
-// BeginTransaction provides a mock function with given fields:
-func (_m *CRUDRepository) BeginTransaction() (pgx.Tx, *utils.ErrorMessage) {
-	ret := _m.Called()
+// BeginTransaction provides a mock function with given fields: ctx
+func (_m *CRUDRepository) BeginTransaction(ctx context.Context) (pgx.Tx, *utils.ErrorMessage) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for BeginTransaction")
@@ -24,19 +28,19 @@ func (_m *CRUDRepository) BeginTransaction() (pgx.Tx, *utils.ErrorMessage) {
 
 	var r0 pgx.Tx
 	var r1 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func() (pgx.Tx, *utils.ErrorMessage)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) (pgx.Tx, *utils.ErrorMessage)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() pgx.Tx); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) pgx.Tx); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(pgx.Tx)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() *utils.ErrorMessage); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) *utils.ErrorMessage); ok {
+		r1 = rf(ctx)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.ErrorMessage)
@@ -46,17 +50,17 @@ func (_m *CRUDRepository) BeginTransaction() (pgx.Tx, *utils.ErrorMessage) {
 	return r0, r1
 }
 
-// CommitTransaction provides a mock function with given fields: tx, objectType
-func (_m *CRUDRepository) CommitTransaction(tx pgx.Tx, objectType string) *utils.ErrorMessage {
-	ret := _m.Called(tx, objectType)
+// CommitTransaction provides a mock function with given fields: ctx, tx, objectType
+func (_m *CRUDRepository) CommitTransaction(ctx context.Context, tx pgx.Tx, objectType string) *utils.ErrorMessage {
+	ret := _m.Called(ctx, tx, objectType)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CommitTransaction")
 	}
 
 	var r0 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(pgx.Tx, string) *utils.ErrorMessage); ok {
-		r0 = rf(tx, objectType)
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx, string) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, tx, objectType)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*utils.ErrorMessage)
@@ -66,10 +70,10 @@ func (_m *CRUDRepository) CommitTransaction(tx pgx.Tx, objectType string) *utils
 	return r0
 }
 
-// Create provides a mock function with given fields: query, objectType, args
-func (_m *CRUDRepository) Create(query string, objectType string, args ...interface{}) (interface{}, *utils.ErrorMessage) {
+// Create provides a mock function with given fields: ctx, query, objectType, args
+func (_m *CRUDRepository) Create(ctx context.Context, query string, objectType string, args ...interface{}) (interface{}, *utils.ErrorMessage) {
 	var _ca []interface{}
-	_ca = append(_ca, query, objectType)
+	_ca = append(_ca, ctx, query, objectType)
 	_ca = append(_ca, args...)
 	ret := _m.Called(_ca...)
 
@@ -79,19 +83,19 @@ func (_m *CRUDRepository) Create(query string, objectType string, args ...interf
 
 	var r0 interface{}
 	var r1 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(string, string, ...interface{}) (interface{}, *utils.ErrorMessage)); ok {
-		return rf(query, objectType, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ...interface{}) (interface{}, *utils.ErrorMessage)); ok {
+		return rf(ctx, query, objectType, args...)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, ...interface{}) interface{}); ok {
-		r0 = rf(query, objectType, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ...interface{}) interface{}); ok {
+		r0 = rf(ctx, query, objectType, args...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(interface{})
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, ...interface{}) *utils.ErrorMessage); ok {
-		r1 = rf(query, objectType, args...)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, ...interface{}) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, query, objectType, args...)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.ErrorMessage)
@@ -101,10 +105,10 @@ func (_m *CRUDRepository) Create(query string, objectType string, args ...interf
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: query, objectType, args
-func (_m *CRUDRepository) Delete(query string, objectType string, args ...interface{}) *utils.ErrorMessage {
+// Delete provides a mock function with given fields: ctx, query, objectType, args
+func (_m *CRUDRepository) Delete(ctx context.Context, query string, objectType string, args ...interface{}) *utils.ErrorMessage {
 	var _ca []interface{}
-	_ca = append(_ca, query, objectType)
+	_ca = append(_ca, ctx, query, objectType)
 	_ca = append(_ca, args...)
 	ret := _m.Called(_ca...)
 
@@ -113,8 +117,8 @@ func (_m *CRUDRepository) Delete(query string, objectType string, args ...interf
 	}
 
 	var r0 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(string, string, ...interface{}) *utils.ErrorMessage); ok {
-		r0 = rf(query, objectType, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ...interface{}) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, query, objectType, args...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*utils.ErrorMessage)
@@ -124,10 +128,10 @@ func (_m *CRUDRepository) Delete(query string, objectType string, args ...interf
 	return r0
 }
 
-// Get provides a mock function with given fields: query, objectType, mapper, args
-func (_m *CRUDRepository) Get(query string, objectType string, mapper utils.RowMapperFunc, args ...interface{}) ([]interface{}, *utils.ErrorMessage) {
+// Get provides a mock function with given fields: ctx, query, objectType, mapper, args
+func (_m *CRUDRepository) Get(ctx context.Context, query string, objectType string, mapper utils.RowMapperFunc, args ...interface{}) ([]interface{}, *utils.ErrorMessage) {
 	var _ca []interface{}
-	_ca = append(_ca, query, objectType, mapper)
+	_ca = append(_ca, ctx, query, objectType, mapper)
 	_ca = append(_ca, args...)
 	ret := _m.Called(_ca...)
 
@@ -137,19 +141,19 @@ func (_m *CRUDRepository) Get(query string, objectType string, mapper utils.RowM
 
 	var r0 []interface{}
 	var r1 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(string, string, utils.RowMapperFunc, ...interface{}) ([]interface{}, *utils.ErrorMessage)); ok {
-		return rf(query, objectType, mapper, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, utils.RowMapperFunc, ...interface{}) ([]interface{}, *utils.ErrorMessage)); ok {
+		return rf(ctx, query, objectType, mapper, args...)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, utils.RowMapperFunc, ...interface{}) []interface{}); ok {
-		r0 = rf(query, objectType, mapper, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, utils.RowMapperFunc, ...interface{}) []interface{}); ok {
+		r0 = rf(ctx, query, objectType, mapper, args...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]interface{})
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, utils.RowMapperFunc, ...interface{}) *utils.ErrorMessage); ok {
-		r1 = rf(query, objectType, mapper, args...)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, utils.RowMapperFunc, ...interface{}) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, query, objectType, mapper, args...)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.ErrorMessage)
@@ -159,10 +163,10 @@ func (_m *CRUDRepository) Get(query string, objectType string, mapper utils.RowM
 	return r0, r1
 }
 
-// GetOne provides a mock function with given fields: query, objectType, mapper, args
-func (_m *CRUDRepository) GetOne(query string, objectType string, mapper utils.RowMapperFunc, args ...interface{}) (interface{}, *utils.ErrorMessage) {
+// GetOne provides a mock function with given fields: ctx, query, objectType, mapper, args
+func (_m *CRUDRepository) GetOne(ctx context.Context, query string, objectType string, mapper utils.RowMapperFunc, args ...interface{}) (interface{}, *utils.ErrorMessage) {
 	var _ca []interface{}
-	_ca = append(_ca, query, objectType, mapper)
+	_ca = append(_ca, ctx, query, objectType, mapper)
 	_ca = append(_ca, args...)
 	ret := _m.Called(_ca...)
 
@@ -172,19 +176,19 @@ func (_m *CRUDRepository) GetOne(query string, objectType string, mapper utils.R
 
 	var r0 interface{}
 	var r1 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(string, string, utils.RowMapperFunc, ...interface{}) (interface{}, *utils.ErrorMessage)); ok {
-		return rf(query, objectType, mapper, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, utils.RowMapperFunc, ...interface{}) (interface{}, *utils.ErrorMessage)); ok {
+		return rf(ctx, query, objectType, mapper, args...)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, utils.RowMapperFunc, ...interface{}) interface{}); ok {
-		r0 = rf(query, objectType, mapper, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, utils.RowMapperFunc, ...interface{}) interface{}); ok {
+		r0 = rf(ctx, query, objectType, mapper, args...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(interface{})
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, utils.RowMapperFunc, ...interface{}) *utils.ErrorMessage); ok {
-		r1 = rf(query, objectType, mapper, args...)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, utils.RowMapperFunc, ...interface{}) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, query, objectType, mapper, args...)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.ErrorMessage)
@@ -194,10 +198,10 @@ func (_m *CRUDRepository) GetOne(query string, objectType string, mapper utils.R
 	return r0, r1
 }
 
-// GetWithPagination provides a mock function with given fields: countSQL, objectType, finalSQL, mapper, pagination, args
-func (_m *CRUDRepository) GetWithPagination(countSQL string, objectType string, finalSQL string, mapper utils.RowMapperFunc, pagination *utils.Pagination, args ...interface{}) (*utils.Pagination, *utils.ErrorMessage) {
+// GetWithPagination provides a mock function with given fields: ctx, countSQL, objectType, finalSQL, mapper, pagination, args
+func (_m *CRUDRepository) GetWithPagination(ctx context.Context, countSQL string, objectType string, finalSQL string, mapper utils.RowMapperFunc, pagination *utils.Pagination, args ...interface{}) (*utils.Pagination, *utils.ErrorMessage) {
 	var _ca []interface{}
-	_ca = append(_ca, countSQL, objectType, finalSQL, mapper, pagination)
+	_ca = append(_ca, ctx, countSQL, objectType, finalSQL, mapper, pagination)
 	_ca = append(_ca, args...)
 	ret := _m.Called(_ca...)
 
@@ -207,19 +211,19 @@ func (_m *CRUDRepository) GetWithPagination(countSQL string, objectType string,
 
 	var r0 *utils.Pagination
 	var r1 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(string, string, string, utils.RowMapperFunc, *utils.Pagination, ...interface{}) (*utils.Pagination, *utils.ErrorMessage)); ok {
-		return rf(countSQL, objectType, finalSQL, mapper, pagination, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, utils.RowMapperFunc, *utils.Pagination, ...interface{}) (*utils.Pagination, *utils.ErrorMessage)); ok {
+		return rf(ctx, countSQL, objectType, finalSQL, mapper, pagination, args...)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string, utils.RowMapperFunc, *utils.Pagination, ...interface{}) *utils.Pagination); ok {
-		r0 = rf(countSQL, objectType, finalSQL, mapper, pagination, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, utils.RowMapperFunc, *utils.Pagination, ...interface{}) *utils.Pagination); ok {
+		r0 = rf(ctx, countSQL, objectType, finalSQL, mapper, pagination, args...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*utils.Pagination)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string, utils.RowMapperFunc, *utils.Pagination, ...interface{}) *utils.ErrorMessage); ok {
-		r1 = rf(countSQL, objectType, finalSQL, mapper, pagination, args...)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, utils.RowMapperFunc, *utils.Pagination, ...interface{}) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, countSQL, objectType, finalSQL, mapper, pagination, args...)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.ErrorMessage)
@@ -249,10 +253,10 @@ func (_m *CRUDRepository) RollBackTransaction(tx pgx.Tx, objectType string) *uti
 	return r0
 }
 
-// Update provides a mock function with given fields: query, objectType, args
-func (_m *CRUDRepository) Update(query string, objectType string, args ...interface{}) *utils.ErrorMessage {
+// Update provides a mock function with given fields: ctx, query, objectType, args
+func (_m *CRUDRepository) Update(ctx context.Context, query string, objectType string, args ...interface{}) *utils.ErrorMessage {
 	var _ca []interface{}
-	_ca = append(_ca, query, objectType)
+	_ca = append(_ca, ctx, query, objectType)
 	_ca = append(_ca, args...)
 	ret := _m.Called(_ca...)
 
@@ -261,8 +265,48 @@ func (_m *CRUDRepository) Update(query string, objectType string, args ...interf
 	}
 
 	var r0 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(string, string, ...interface{}) *utils.ErrorMessage); ok {
-		r0 = rf(query, objectType, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ...interface{}) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, query, objectType, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0
+}
+
+// WithTransaction provides a mock function with given fields: ctx, fn
+func (_m *CRUDRepository) WithTransaction(ctx context.Context, fn Repository.TxFunc) *utils.ErrorMessage {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTransaction")
+	}
+
+	var r0 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, Repository.TxFunc) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0
+}
+
+// WithTransactionOptions provides a mock function with given fields: ctx, options, fn
+func (_m *CRUDRepository) WithTransactionOptions(ctx context.Context, options pgx.TxOptions, fn Repository.TxFunc) *utils.ErrorMessage {
+	ret := _m.Called(ctx, options, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTransactionOptions")
+	}
+
+	var r0 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.TxOptions, Repository.TxFunc) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, options, fn)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*utils.ErrorMessage)
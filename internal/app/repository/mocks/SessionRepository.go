@@ -0,0 +1,179 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	Repository "starter/internal/app/repository"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "starter/internal/app/models"
+
+	time "time"
+
+	utils "starter/internal/app/utils"
+)
+
+// SessionRepository is an autogenerated mock type for the SessionRepository type
+type SessionRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, session
+func (_m *SessionRepository) Create(ctx context.Context, session *models.Session) (*models.Session, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *models.Session
+	var r1 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Session) (*models.Session, *utils.ErrorMessage)); ok {
+		return rf(ctx, session)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Session) *models.Session); ok {
+		r0 = rf(ctx, session)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Session) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, session)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, sessionId
+func (_m *SessionRepository) Delete(ctx context.Context, sessionId string) *utils.ErrorMessage {
+	ret := _m.Called(ctx, sessionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, string) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, sessionId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0
+}
+
+// DeleteByUserID provides a mock function with given fields: ctx, userId
+func (_m *SessionRepository) DeleteByUserID(ctx context.Context, userId int64) *utils.ErrorMessage {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByUserID")
+	}
+
+	var r0 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: ctx, before
+func (_m *SessionRepository) DeleteExpired(ctx context.Context, before time.Time) *utils.ErrorMessage {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, sessionId
+func (_m *SessionRepository) Get(ctx context.Context, sessionId string) (*models.Session, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, sessionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *models.Session
+	var r1 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Session, *utils.ErrorMessage)); ok {
+		return rf(ctx, sessionId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Session); ok {
+		r0 = rf(ctx, sessionId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, sessionId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0, r1
+}
+
+// WithTx provides a mock function with given fields: txRepo
+func (_m *SessionRepository) WithTx(txRepo Repository.CRUDRepository) Repository.SessionRepository {
+	ret := _m.Called(txRepo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 Repository.SessionRepository
+	if rf, ok := ret.Get(0).(func(Repository.CRUDRepository) Repository.SessionRepository); ok {
+		r0 = rf(txRepo)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(Repository.SessionRepository)
+		}
+	}
+
+	return r0
+}
+
+// NewSessionRepository creates a new instance of SessionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionRepository {
+	mock := &SessionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,10 +3,13 @@
 package mocks
 
 import (
-	models "starter/internal/app/models"
+	context "context"
+	Repository "starter/internal/app/repository"
 
 	mock "github.com/stretchr/testify/mock"
 
+	models "starter/internal/app/models"
+
 	utils "starter/internal/app/utils"
 )
 
@@ -15,9 +18,9 @@ type UserRepository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: user
-func (_m *UserRepository) Create(user *models.User) (*models.User, *utils.ErrorMessage) {
-	ret := _m.Called(user)
+// Create provides a mock function with given fields: ctx, user
+func (_m *UserRepository) Create(ctx context.Context, user *models.User) (*models.User, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
@@ -25,19 +28,19 @@ func (_m *UserRepository) Create(user *models.User) (*models.User, *utils.ErrorM
 
 	var r0 *models.User
 	var r1 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(*models.User) (*models.User, *utils.ErrorMessage)); ok {
-		return rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) (*models.User, *utils.ErrorMessage)); ok {
+		return rf(ctx, user)
 	}
-	if rf, ok := ret.Get(0).(func(*models.User) *models.User); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) *models.User); ok {
+		r0 = rf(ctx, user)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*models.User) *utils.ErrorMessage); ok {
-		r1 = rf(user)
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, user)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.ErrorMessage)
@@ -47,17 +50,17 @@ func (_m *UserRepository) Create(user *models.User) (*models.User, *utils.ErrorM
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: emailId
-func (_m *UserRepository) Delete(emailId string) *utils.ErrorMessage {
-	ret := _m.Called(emailId)
+// Delete provides a mock function with given fields: ctx, emailId
+func (_m *UserRepository) Delete(ctx context.Context, emailId string) *utils.ErrorMessage {
+	ret := _m.Called(ctx, emailId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
 	var r0 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(string) *utils.ErrorMessage); ok {
-		r0 = rf(emailId)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, emailId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*utils.ErrorMessage)
@@ -67,9 +70,9 @@ func (_m *UserRepository) Delete(emailId string) *utils.ErrorMessage {
 	return r0
 }
 
-// Get provides a mock function with given fields: emailId
-func (_m *UserRepository) Get(emailId string) (*models.User, *utils.ErrorMessage) {
-	ret := _m.Called(emailId)
+// Get provides a mock function with given fields: ctx, emailId
+func (_m *UserRepository) Get(ctx context.Context, emailId string) (*models.User, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, emailId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Get")
@@ -77,19 +80,51 @@ func (_m *UserRepository) Get(emailId string) (*models.User, *utils.ErrorMessage
 
 	var r0 *models.User
 	var r1 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(string) (*models.User, *utils.ErrorMessage)); ok {
-		return rf(emailId)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, *utils.ErrorMessage)); ok {
+		return rf(ctx, emailId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, emailId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, emailId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetProfile provides a mock function with given fields: ctx, emailId
+func (_m *UserRepository) GetProfile(ctx context.Context, emailId string) (*models.User, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, emailId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfile")
+	}
+
+	var r0 *models.User
+	var r1 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, *utils.ErrorMessage)); ok {
+		return rf(ctx, emailId)
 	}
-	if rf, ok := ret.Get(0).(func(string) *models.User); ok {
-		r0 = rf(emailId)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, emailId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) *utils.ErrorMessage); ok {
-		r1 = rf(emailId)
+	if rf, ok := ret.Get(1).(func(context.Context, string) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, emailId)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.ErrorMessage)
@@ -99,9 +134,9 @@ func (_m *UserRepository) Get(emailId string) (*models.User, *utils.ErrorMessage
 	return r0, r1
 }
 
-// GetUserByID provides a mock function with given fields: id
-func (_m *UserRepository) GetUserByID(id int64) (*models.User, *utils.ErrorMessage) {
-	ret := _m.Called(id)
+// GetUserByID provides a mock function with given fields: ctx, id
+func (_m *UserRepository) GetUserByID(ctx context.Context, id int64) (*models.User, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserByID")
@@ -109,19 +144,19 @@ func (_m *UserRepository) GetUserByID(id int64) (*models.User, *utils.ErrorMessa
 
 	var r0 *models.User
 	var r1 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(int64) (*models.User, *utils.ErrorMessage)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*models.User, *utils.ErrorMessage)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int64) *models.User); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.User); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) *utils.ErrorMessage); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, id)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.ErrorMessage)
@@ -131,9 +166,9 @@ func (_m *UserRepository) GetUserByID(id int64) (*models.User, *utils.ErrorMessa
 	return r0, r1
 }
 
-// ListAllUsers provides a mock function with given fields:
-func (_m *UserRepository) ListAllUsers() ([]interface{}, *utils.ErrorMessage) {
-	ret := _m.Called()
+// ListAllUsers provides a mock function with given fields: ctx
+func (_m *UserRepository) ListAllUsers(ctx context.Context) ([]interface{}, *utils.ErrorMessage) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListAllUsers")
@@ -141,19 +176,19 @@ func (_m *UserRepository) ListAllUsers() ([]interface{}, *utils.ErrorMessage) {
 
 	var r0 []interface{}
 	var r1 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func() ([]interface{}, *utils.ErrorMessage)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]interface{}, *utils.ErrorMessage)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []interface{}); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []interface{}); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]interface{})
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() *utils.ErrorMessage); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) *utils.ErrorMessage); ok {
+		r1 = rf(ctx)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.ErrorMessage)
@@ -163,17 +198,79 @@ func (_m *UserRepository) ListAllUsers() ([]interface{}, *utils.ErrorMessage) {
 	return r0, r1
 }
 
-// UpdatePassword provides a mock function with given fields: email, hashedPass, salt
-func (_m *UserRepository) UpdatePassword(email string, hashedPass string, salt string) *utils.ErrorMessage {
-	ret := _m.Called(email, hashedPass, salt)
+// ListUsers provides a mock function with given fields: ctx, pagination, filter
+func (_m *UserRepository) ListUsers(ctx context.Context, pagination *utils.Pagination, filter *models.UserFilter) (*utils.Pagination, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, pagination, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 *utils.Pagination
+	var r1 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, *utils.Pagination, *models.UserFilter) (*utils.Pagination, *utils.ErrorMessage)); ok {
+		return rf(ctx, pagination, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *utils.Pagination, *models.UserFilter) *utils.Pagination); ok {
+		r0 = rf(ctx, pagination, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*utils.Pagination)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *utils.Pagination, *models.UserFilter) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, pagination, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0, r1
+}
+
+// Reencrypt provides a mock function with given fields: ctx
+func (_m *UserRepository) Reencrypt(ctx context.Context) (int, *utils.ErrorMessage) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reencrypt")
+	}
+
+	var r0 int
+	var r1 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context) (int, *utils.ErrorMessage)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) *utils.ErrorMessage); ok {
+		r1 = rf(ctx)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdatePassword provides a mock function with given fields: ctx, email, hashedPass, salt
+func (_m *UserRepository) UpdatePassword(ctx context.Context, email string, hashedPass string, salt string) *utils.ErrorMessage {
+	ret := _m.Called(ctx, email, hashedPass, salt)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdatePassword")
 	}
 
 	var r0 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(string, string, string) *utils.ErrorMessage); ok {
-		r0 = rf(email, hashedPass, salt)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, email, hashedPass, salt)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*utils.ErrorMessage)
@@ -183,17 +280,17 @@ func (_m *UserRepository) UpdatePassword(email string, hashedPass string, salt s
 	return r0
 }
 
-// UpdateUserSelfDetails provides a mock function with given fields: currentEmail, user
-func (_m *UserRepository) UpdateUserSelfDetails(currentEmail string, user *models.User) *utils.ErrorMessage {
-	ret := _m.Called(currentEmail, user)
+// UpdateUserSelfDetails provides a mock function with given fields: ctx, currentEmail, user
+func (_m *UserRepository) UpdateUserSelfDetails(ctx context.Context, currentEmail string, user *models.User) *utils.ErrorMessage {
+	ret := _m.Called(ctx, currentEmail, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUserSelfDetails")
 	}
 
 	var r0 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(string, *models.User) *utils.ErrorMessage); ok {
-		r0 = rf(currentEmail, user)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.User) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, currentEmail, user)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*utils.ErrorMessage)
@@ -203,6 +300,26 @@ func (_m *UserRepository) UpdateUserSelfDetails(currentEmail string, user *model
 	return r0
 }
 
+// WithTx provides a mock function with given fields: txRepo
+func (_m *UserRepository) WithTx(txRepo Repository.CRUDRepository) Repository.UserRepository {
+	ret := _m.Called(txRepo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 Repository.UserRepository
+	if rf, ok := ret.Get(0).(func(Repository.CRUDRepository) Repository.UserRepository); ok {
+		r0 = rf(txRepo)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(Repository.UserRepository)
+		}
+	}
+
+	return r0
+}
+
 // NewUserRepository creates a new instance of UserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepository(t interface {
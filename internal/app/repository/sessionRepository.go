@@ -0,0 +1,83 @@
+package Repository
+
+import (
+	"context"
+	"starter/internal/app/logging"
+	"starter/internal/app/models"
+	"starter/internal/app/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const SESSION = "sessions"
+
+func NewSessionRepository(crudRepository CRUDRepository) SessionRepository {
+	return &SessionRepoHandler{
+		crudRepository: crudRepository,
+	}
+}
+
+//go:generate mockery --name SessionRepository
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) (*models.Session, *utils.ErrorMessage)
+	Get(ctx context.Context, sessionId string) (*models.Session, *utils.ErrorMessage)
+	Delete(ctx context.Context, sessionId string) *utils.ErrorMessage
+	DeleteByUserID(ctx context.Context, userId int64) *utils.ErrorMessage
+	DeleteExpired(ctx context.Context, before time.Time) *utils.ErrorMessage
+	WithTx(txRepo CRUDRepository) SessionRepository
+}
+
+type SessionRepoHandler struct {
+	crudRepository CRUDRepository
+}
+
+// WithTx returns a copy of the repository running its statements through the transaction of txRepo.
+func (s *SessionRepoHandler) WithTx(txRepo CRUDRepository) SessionRepository {
+	return &SessionRepoHandler{crudRepository: txRepo}
+}
+
+func (s *SessionRepoHandler) Create(ctx context.Context, session *models.Session) (*models.Session, *utils.ErrorMessage) {
+	logging.ForComponent(ctx, logComponent).Debug("Creating session for User:", session.UserID)
+	query := `INSERT INTO "public"."sessions" ("id", "userId", "inserted_at", "expires_at")
+			VALUES ($1, $2, $3, $4) RETURNING "id"`
+	if _, err := s.crudRepository.Create(ctx, query, SESSION, session.ID, session.UserID, session.InsertedAt, session.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *SessionRepoHandler) Get(ctx context.Context, sessionId string) (*models.Session, *utils.ErrorMessage) {
+	query := `SELECT "id", "userId", "inserted_at", "expires_at"
+			FROM "public"."sessions"
+			WHERE "id"=$1;`
+	session, err := s.crudRepository.GetOne(ctx, query, SESSION, sessionMapper, sessionId)
+	v, _ := session.(*models.Session)
+	return v, err
+}
+
+func (s *SessionRepoHandler) Delete(ctx context.Context, sessionId string) *utils.ErrorMessage {
+	query := `DELETE FROM "public"."sessions" WHERE "id"=$1`
+	return s.crudRepository.Delete(ctx, query, SESSION, sessionId)
+}
+
+// DeleteByUserID revokes every session of the user.
+func (s *SessionRepoHandler) DeleteByUserID(ctx context.Context, userId int64) *utils.ErrorMessage {
+	query := `DELETE FROM "public"."sessions" WHERE "userId"=$1`
+	return s.crudRepository.Delete(ctx, query, SESSION, userId)
+}
+
+// DeleteExpired removes the sessions that expired before the given time.
+func (s *SessionRepoHandler) DeleteExpired(ctx context.Context, before time.Time) *utils.ErrorMessage {
+	query := `DELETE FROM "public"."sessions" WHERE "expires_at"<$1`
+	return s.crudRepository.Delete(ctx, query, SESSION, before)
+}
+
+var sessionMapper = func(row pgx.Row) (interface{}, error) {
+	var session models.Session
+	err := row.Scan(&session.ID, &session.UserID, &session.InsertedAt, &session.ExpiresAt)
+	if err != nil {
+		logging.Component(logComponent).Errorf("Failed to scan session: %v", err)
+	}
+	return &session, err
+}
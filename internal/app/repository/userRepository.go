@@ -1,118 +1,300 @@
 package Repository
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"starter/internal/app/constants"
+	"starter/internal/app/encryption"
+	"starter/internal/app/logging"
 	"starter/internal/app/models"
 	"starter/internal/app/utils"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/sirupsen/logrus"
 )
 
 const USER = "users"
 
-func NewUserRepository(crudRepository CRUDRepository) UserRepository {
+func NewUserRepository(crudRepository CRUDRepository, fieldCipher encryption.FieldCipher) UserRepository {
 	return &UserRepoHandler{
 		crudRepository: crudRepository,
+		fieldCipher:    fieldCipher,
 	}
 }
 
 //go:generate mockery --name UserRepository
 type UserRepository interface {
-	Create(user *models.User) (*models.User, *utils.ErrorMessage)
-	Delete(emailId string) *utils.ErrorMessage
-	Get(emailId string) (*models.User, *utils.ErrorMessage)
-	UpdatePassword(email string, hashedPass string, salt string) *utils.ErrorMessage
+	Create(ctx context.Context, user *models.User) (*models.User, *utils.ErrorMessage)
+	Delete(ctx context.Context, emailId string) *utils.ErrorMessage
+	Get(ctx context.Context, emailId string) (*models.User, *utils.ErrorMessage)
+	GetProfile(ctx context.Context, emailId string) (*models.User, *utils.ErrorMessage)
+	UpdatePassword(ctx context.Context, email string, hashedPass string, salt string) *utils.ErrorMessage
 
-	UpdateUserSelfDetails(currentEmail string, user *models.User) *utils.ErrorMessage
-	ListAllUsers() ([]interface{}, *utils.ErrorMessage)
-	GetUserByID(id int64) (*models.User, *utils.ErrorMessage)
+	UpdateUserSelfDetails(ctx context.Context, currentEmail string, user *models.User) *utils.ErrorMessage
+	ListAllUsers(ctx context.Context) ([]interface{}, *utils.ErrorMessage)
+	ListUsers(ctx context.Context, pagination *utils.Pagination, filter *models.UserFilter) (*utils.Pagination, *utils.ErrorMessage)
+	GetUserByID(ctx context.Context, id int64) (*models.User, *utils.ErrorMessage)
+	Reencrypt(ctx context.Context) (int, *utils.ErrorMessage)
+	WithTx(txRepo CRUDRepository) UserRepository
 }
 
+// UserRepoHandler stores the email and names of users encrypted with fieldCipher. Users are
+// looked up by email through the "userEmailIndex" blind index, never by the encrypted column.
 type UserRepoHandler struct {
 	crudRepository CRUDRepository
+	fieldCipher    encryption.FieldCipher
 }
 
-func (u *UserRepoHandler) Create(user *models.User) (*models.User, *utils.ErrorMessage) {
-	logrus.Debug("Creating User")
-	query := `INSERT INTO "public"."users" ("userEmailId", "encrypted_password", "inserted_at", "updated_at", "userDisplayName","userFirstName","userLastName","userRole","stored_salt")
-			VALUES ($1, $2, $3, $4, $5,$6 ,$7,$8,$9) RETURNING "id"`
-	id, err := u.crudRepository.Create(query, USER, user.UserEmailId, user.EncryptedPassword, user.InsertedAt, user.UpdatedAt, user.UserDisplayName, user.UserFirstName, user.UserLastName, user.UserRole, user.StoredSalt)
+// WithTx returns a copy of the repository running its statements through the transaction of txRepo.
+func (u *UserRepoHandler) WithTx(txRepo CRUDRepository) UserRepository {
+	return &UserRepoHandler{crudRepository: txRepo, fieldCipher: u.fieldCipher}
+}
+
+// sealedUser holds the encrypted columns of a user and the blind index of its email.
+type sealedUser struct {
+	email, emailIndex, displayName, firstName, lastName string
+}
+
+func (u *UserRepoHandler) seal(user *models.User) (*sealedUser, *utils.ErrorMessage) {
+	sealed := &sealedUser{emailIndex: u.fieldCipher.BlindIndex(user.UserEmailId)}
+	for _, field := range []struct {
+		plaintext string
+		target    *string
+	}{
+		{user.UserEmailId, &sealed.email},
+		{user.UserDisplayName, &sealed.displayName},
+		{user.UserFirstName, &sealed.firstName},
+		{user.UserLastName, &sealed.lastName},
+	} {
+		ciphertext, err := u.fieldCipher.Encrypt(field.plaintext)
+		if err != nil {
+			logging.Component(logComponent).Errorf("Failed to encrypt user: %v", err)
+			return nil, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: fmt.Sprintf(constants.FAILED_TO_ENCRYPT, USER)}
+		}
+		*field.target = ciphertext
+	}
+	return sealed, nil
+}
+
+// open decrypts the encrypted columns of a scanned user in place.
+func (u *UserRepoHandler) open(user *models.User) error {
+	for _, field := range []*string{&user.UserEmailId, &user.UserDisplayName, &user.UserFirstName, &user.UserLastName} {
+		plaintext, err := u.fieldCipher.Decrypt(*field)
+		if err != nil {
+			return fmt.Errorf("user %d: %w", user.ID, err)
+		}
+		*field = plaintext
+	}
+	return nil
+}
+
+func (u *UserRepoHandler) Create(ctx context.Context, user *models.User) (*models.User, *utils.ErrorMessage) {
+	logging.ForComponent(ctx, logComponent).Debug("Creating User")
+	sealed, sealErr := u.seal(user)
+	if sealErr != nil {
+		return user, sealErr
+	}
+	query := `INSERT INTO "public"."users" ("userEmailId", "userEmailIndex", "encrypted_password", "inserted_at", "updated_at", "userDisplayName","userFirstName","userLastName","userRole","stored_salt")
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING "id"`
+	id, err := u.crudRepository.Create(ctx, query, USER, sealed.email, sealed.emailIndex, user.EncryptedPassword, user.InsertedAt, user.UpdatedAt,
+		sealed.displayName, sealed.firstName, sealed.lastName, user.UserRole, user.StoredSalt)
 	v, _ := id.(int64)
 	user.ID = v
 	return user, err
 }
 
-func (u *UserRepoHandler) Delete(emailId string) *utils.ErrorMessage {
-	logrus.Debug("Getting User from EmailId:", emailId)
-	query := `DELETE FROM "public"."users" WHERE "userEmailId"=$1`
-	if err := u.crudRepository.Delete(query, USER, emailId); err != nil {
-		logrus.Error("Failed to delete User from database")
+func (u *UserRepoHandler) Delete(ctx context.Context, emailId string) *utils.ErrorMessage {
+	logging.ForComponent(ctx, logComponent).Debug("Getting User from EmailId:", emailId)
+	query := `DELETE FROM "public"."users" WHERE "userEmailIndex"=$1`
+	if err := u.crudRepository.Delete(ctx, query, USER, u.fieldCipher.BlindIndex(emailId)); err != nil {
+		logging.ForComponent(ctx, logComponent).Error("Failed to delete User from database")
 		return err
 	}
 	return nil
 }
 
-func (u *UserRepoHandler) GetUserByID(id int64) (*models.User, *utils.ErrorMessage) {
+func (u *UserRepoHandler) GetUserByID(ctx context.Context, id int64) (*models.User, *utils.ErrorMessage) {
 	query := `SELECT "id", "userEmailId", "inserted_at", "updated_at",
        			   "userDisplayName", "userFirstName", "userLastName", "userRole" 
 			FROM "public"."users" 
 			WHERE "id"=$1;`
-	user, err := u.crudRepository.GetOne(query, USER, userMapperWithoutPassword, id)
+	user, err := u.crudRepository.GetOne(ctx, query, USER, u.userMapperWithoutPassword, id)
+	v, _ := user.(*models.User)
+	return v, err
+}
+
+// GetProfile loads the user by email without the password hash and salt.
+func (u *UserRepoHandler) GetProfile(ctx context.Context, emailId string) (*models.User, *utils.ErrorMessage) {
+	logging.ForComponent(ctx, logComponent).Debug("Getting User profile from EmailId:", emailId)
+	query := `SELECT "id", "userEmailId", "inserted_at", "updated_at",
+       			   "userDisplayName", "userFirstName", "userLastName", "userRole"
+			FROM "public"."users"
+			WHERE "userEmailIndex"=$1;`
+	user, err := u.crudRepository.GetOne(ctx, query, USER, u.userMapperWithoutPassword, u.fieldCipher.BlindIndex(emailId))
 	v, _ := user.(*models.User)
 	return v, err
 }
 
-func (u *UserRepoHandler) Get(emailId string) (*models.User, *utils.ErrorMessage) {
-	logrus.Debug("Getting User from EmailId:", emailId)
-	query := `SELECT "id","userEmailId","encrypted_password","inserted_at","updated_at","userDisplayName","userFirstName","userLastName","userRole","stored_salt"  FROM "public"."users" WHERE "userEmailId"=$1;`
-	user, err := u.crudRepository.GetOne(query, USER, userMapper, emailId)
+// Get loads the user by email including the password hash and salt, for credential checks only.
+func (u *UserRepoHandler) Get(ctx context.Context, emailId string) (*models.User, *utils.ErrorMessage) {
+	logging.ForComponent(ctx, logComponent).Debug("Getting User from EmailId:", emailId)
+	query := `SELECT "id","userEmailId","encrypted_password","inserted_at","updated_at","userDisplayName","userFirstName","userLastName","userRole","stored_salt"  FROM "public"."users" WHERE "userEmailIndex"=$1;`
+	user, err := u.crudRepository.GetOne(ctx, query, USER, u.userMapper, u.fieldCipher.BlindIndex(emailId))
 	v, _ := user.(*models.User)
 	return v, err
 }
 
-func (u *UserRepoHandler) UpdatePassword(email string, hashedPass string, salt string) *utils.ErrorMessage {
+func (u *UserRepoHandler) UpdatePassword(ctx context.Context, email string, hashedPass string, salt string) *utils.ErrorMessage {
 	query := `UPDATE "public"."users" 
 			SET "encrypted_password"=$2, 
 			    "stored_salt"=$3
-			WHERE "userEmailId"=$1;`
-	return u.crudRepository.Update(query, USER, email, hashedPass, salt)
+			WHERE "userEmailIndex"=$1;`
+	return u.crudRepository.Update(ctx, query, USER, u.fieldCipher.BlindIndex(email), hashedPass, salt)
 }
 
-func (u *UserRepoHandler) UpdateUserSelfDetails(currentEmail string, user *models.User) *utils.ErrorMessage {
+func (u *UserRepoHandler) UpdateUserSelfDetails(ctx context.Context, currentEmail string, user *models.User) *utils.ErrorMessage {
+	sealed, sealErr := u.seal(user)
+	if sealErr != nil {
+		return sealErr
+	}
 	query := `UPDATE  "public"."users"  
 		   SET     "userEmailId"= $1,
+		           "userEmailIndex"=$2,
 		           "updated_at"=NOW(),
-		           "userDisplayName"=$2,
-		           "userFirstName"=$3,
-		           "userLastName"=$4
-		   WHERE "userEmailId"=$5;`
-	return u.crudRepository.Update(query, USER,
-		user.UserEmailId, user.UserDisplayName,
-		user.UserFirstName, user.UserLastName,
-		currentEmail)
+		           "userDisplayName"=$3,
+		           "userFirstName"=$4,
+		           "userLastName"=$5
+		   WHERE "userEmailIndex"=$6;`
+	return u.crudRepository.Update(ctx, query, USER,
+		sealed.email, sealed.emailIndex, sealed.displayName,
+		sealed.firstName, sealed.lastName,
+		u.fieldCipher.BlindIndex(currentEmail))
 }
 
-func (u *UserRepoHandler) ListAllUsers() ([]interface{}, *utils.ErrorMessage) {
+func (u *UserRepoHandler) ListAllUsers(ctx context.Context) ([]interface{}, *utils.ErrorMessage) {
 	query := `SELECT "id", "userEmailId", "inserted_at", "updated_at",
        			   "userDisplayName", "userFirstName", "userLastName", "userRole" 
 			FROM "public"."users";`
-	return u.crudRepository.Get(query, USER, userMapperWithoutPassword)
+	return u.crudRepository.Get(ctx, query, USER, u.userMapperWithoutPassword)
+}
+
+// ListUsers returns one page of users matching the filter. The sort clause must come from
+// utils.PaginateQueryExtractor with models.UserSortFields, every filter value is passed as a parameter.
+// Encrypted columns can only be filtered by exact email, through the blind index.
+func (u *UserRepoHandler) ListUsers(ctx context.Context, pagination *utils.Pagination, filter *models.UserFilter) (*utils.Pagination, *utils.ErrorMessage) {
+	var conditions []string
+	var args []any
+	if len(filter.Role) > 0 {
+		args = append(args, filter.Role)
+		conditions = append(conditions, fmt.Sprintf(`"userRole"=$%d`, len(args)))
+	}
+	if len(filter.Email) > 0 {
+		args = append(args, u.fieldCipher.BlindIndex(filter.Email))
+		conditions = append(conditions, fmt.Sprintf(`"userEmailIndex"=$%d`, len(args)))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		args = append(args, filter.CreatedAfter)
+		conditions = append(conditions, fmt.Sprintf(`"inserted_at">$%d`, len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	countSQL := `SELECT COUNT(*) FROM "public"."users"` + where
+	finalSQL := fmt.Sprintf(`SELECT "id", "userEmailId", "inserted_at", "updated_at",
+       			   "userDisplayName", "userFirstName", "userLastName", "userRole"
+			FROM "public"."users"%s
+			ORDER BY %s
+			LIMIT %d OFFSET %d;`, where, pagination.GetSort(), pagination.GetLimit(), pagination.GetOffset())
+	return u.crudRepository.GetWithPagination(ctx, countSQL, USER, finalSQL, u.userMapperWithoutPassword, pagination, args...)
 }
 
-var userMapperWithoutPassword = func(row pgx.Row) (interface{}, error) {
+// Reencrypt rewrites the users stored in plaintext, under an older key or without an email index
+// with the active key, and returns how many were rewritten. It is safe to run again after a failure.
+func (u *UserRepoHandler) Reencrypt(ctx context.Context) (int, *utils.ErrorMessage) {
+	query := `SELECT "id", "userEmailId", "userEmailIndex", "userDisplayName", "userFirstName", "userLastName"
+			FROM "public"."users" ORDER BY "id";`
+	rows, err := u.crudRepository.Get(ctx, query, USER, storedUserMapper)
+	if err != nil {
+		return 0, err
+	}
+	rewritten := 0
+	for _, row := range rows {
+		stored, _ := row.(*storedUser)
+		if !u.needsReencryption(stored) {
+			continue
+		}
+		if openErr := u.open(&stored.user); openErr != nil {
+			logging.ForComponent(ctx, logComponent).Errorf("Failed to decrypt user: %v", openErr)
+			return rewritten, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: fmt.Sprintf(constants.FAILED_TO_DECRYPT, USER)}
+		}
+		sealed, sealErr := u.seal(&stored.user)
+		if sealErr != nil {
+			return rewritten, sealErr
+		}
+		update := `UPDATE "public"."users"
+			SET "userEmailId"=$1, "userEmailIndex"=$2, "userDisplayName"=$3, "userFirstName"=$4, "userLastName"=$5
+			WHERE "id"=$6;`
+		if err := u.crudRepository.Update(ctx, update, USER, sealed.email, sealed.emailIndex,
+			sealed.displayName, sealed.firstName, sealed.lastName, stored.user.ID); err != nil {
+			return rewritten, err
+		}
+		rewritten++
+	}
+	return rewritten, nil
+}
+
+func (u *UserRepoHandler) needsReencryption(stored *storedUser) bool {
+	if stored.emailIndex == nil {
+		return true
+	}
+	for _, value := range []string{stored.user.UserEmailId, stored.user.UserDisplayName, stored.user.UserFirstName, stored.user.UserLastName} {
+		if u.fieldCipher.NeedsRotation(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// storedUser is a user row as stored, still encrypted, with its possibly missing email index.
+type storedUser struct {
+	user       models.User
+	emailIndex *string
+}
+
+var storedUserMapper = func(row pgx.Row) (interface{}, error) {
+	var stored storedUser
+	err := row.Scan(&stored.user.ID, &stored.user.UserEmailId, &stored.emailIndex, &stored.user.UserDisplayName, &stored.user.UserFirstName, &stored.user.UserLastName)
+	if err != nil {
+		logging.Component(logComponent).Errorf("Failed to scan user: %v", err)
+	}
+	return &stored, err
+}
+
+func (u *UserRepoHandler) userMapperWithoutPassword(row pgx.Row) (interface{}, error) {
 	var user models.User
 	err := row.Scan(&user.ID, &user.UserEmailId, &user.InsertedAt, &user.UpdatedAt, &user.UserDisplayName, &user.UserFirstName, &user.UserLastName, &user.UserRole)
 	if err != nil {
-		logrus.Errorf("Failed to scan user: %v", err)
+		logging.Component(logComponent).Errorf("Failed to scan user: %v", err)
+		return &user, err
+	}
+	if err = u.open(&user); err != nil {
+		logging.Component(logComponent).Errorf("Failed to decrypt user: %v", err)
 	}
 	return &user, err
 }
 
-var userMapper = func(row pgx.Row) (interface{}, error) {
+func (u *UserRepoHandler) userMapper(row pgx.Row) (interface{}, error) {
 	var user models.User
 	err := row.Scan(&user.ID, &user.UserEmailId, &user.EncryptedPassword, &user.InsertedAt, &user.UpdatedAt, &user.UserDisplayName, &user.UserFirstName, &user.UserLastName, &user.UserRole, &user.StoredSalt)
 	if err != nil {
-		logrus.Errorf("Failed to scan user: %v", err)
+		logging.Component(logComponent).Errorf("Failed to scan user: %v", err)
+		return &user, err
+	}
+	if err = u.open(&user); err != nil {
+		logging.Component(logComponent).Errorf("Failed to decrypt user: %v", err)
 	}
 	return &user, err
 }
@@ -0,0 +1,98 @@
+package Repository
+
+import (
+	"context"
+	"starter/internal/app/encryption"
+	"starter/internal/app/models"
+	"starter/internal/app/utils"
+	"starter/internal/config"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ListUsers_Filters(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	fieldCipher := encryption.NewFieldCipher(config.Defaults().Security)
+	userRepo := NewUserRepository(NewCRUDRepository(dbMock, config.Defaults().Database), fieldCipher)
+	defer dbMock.Close()
+	emailIndex := fieldCipher.BlindIndex("a@example.com")
+	dbMock.ExpectQuery(`SELECT "id".* WHERE "userRole"=\$1 AND "userEmailIndex"=\$2 ORDER BY "inserted_at" ASC LIMIT 5 OFFSET 5`).
+		WithArgs(models.RoleAdmin, emailIndex).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "userEmailId", "inserted_at", "updated_at", "userDisplayName", "userFirstName", "userLastName", "userRole"}))
+	dbMock.ExpectQuery(`SELECT COUNT\(\*\) FROM "public"."users" WHERE "userRole"=\$1 AND "userEmailIndex"=\$2`).
+		WithArgs(models.RoleAdmin, emailIndex).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(6)))
+
+	page, err := userRepo.ListUsers(context.Background(), &utils.Pagination{Page: 2, Limit: 5, Sort: `"inserted_at" ASC`},
+		&models.UserFilter{Role: models.RoleAdmin, Email: "A@example.com"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), page.TotalRows)
+	assert.Equal(t, 2, page.TotalPages)
+	if e := dbMock.ExpectationsWereMet(); e != nil {
+		t.Errorf("there were unfulfilled expectations: %s", e)
+	}
+}
+
+func Test_GetUser_ByBlindIndexDecryptsFields(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	fieldCipher := encryption.NewFieldCipher(config.Defaults().Security)
+	userRepo := NewUserRepository(NewCRUDRepository(dbMock, config.Defaults().Database), fieldCipher)
+	defer dbMock.Close()
+	email, _ := fieldCipher.Encrypt("jane@example.com")
+	firstName, _ := fieldCipher.Encrypt("Jane")
+	dbMock.ExpectQuery(`SELECT "id", "userEmailId".* WHERE "userEmailIndex"=\$1`).
+		WithArgs(fieldCipher.BlindIndex("Jane@Example.com")).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "userEmailId", "inserted_at", "updated_at", "userDisplayName", "userFirstName", "userLastName", "userRole"}).
+			AddRow(int64(1), email, time.Now(), time.Now(), "legacy plaintext", firstName, "", models.RoleViewer))
+
+	user, err := userRepo.GetProfile(context.Background(), "Jane@Example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, "jane@example.com", user.UserEmailId)
+	assert.Equal(t, "Jane", user.UserFirstName)
+	assert.Equal(t, "legacy plaintext", user.UserDisplayName)
+	if e := dbMock.ExpectationsWereMet(); e != nil {
+		t.Errorf("there were unfulfilled expectations: %s", e)
+	}
+}
+
+func Test_CreateUser_StoresCiphertext(t *testing.T) {
+	dbMock, _ := pgxmock.NewPool()
+	dbMock.ExpectPing().WillReturnError(nil)
+	fieldCipher := encryption.NewFieldCipher(config.Defaults().Security)
+	userRepo := NewUserRepository(NewCRUDRepository(dbMock, config.Defaults().Database), fieldCipher)
+	defer dbMock.Close()
+	user := &models.User{UserEmailId: "jane@example.com", UserFirstName: "Jane", UserRole: models.RoleViewer}
+	dbMock.ExpectBegin()
+	dbMock.ExpectQuery(`INSERT INTO "public"."users"`).
+		WithArgs(encryptedArg{"jane@example.com"}, fieldCipher.BlindIndex("jane@example.com"), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+			"", encryptedArg{"Jane"}, "", models.RoleViewer, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(3)))
+	dbMock.ExpectCommit()
+
+	created, err := userRepo.Create(context.Background(), user)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), created.ID)
+	assert.Equal(t, "jane@example.com", created.UserEmailId)
+	if e := dbMock.ExpectationsWereMet(); e != nil {
+		t.Errorf("there were unfulfilled expectations: %s", e)
+	}
+}
+
+// encryptedArg matches an argument encrypted from plaintext.
+type encryptedArg struct {
+	plaintext string
+}
+
+func (e encryptedArg) Match(value interface{}) bool {
+	ciphertext, ok := value.(string)
+	if !ok || ciphertext == e.plaintext {
+		return false
+	}
+	plaintext, err := encryption.NewFieldCipher(config.Defaults().Security).Decrypt(ciphertext)
+	return err == nil && plaintext == e.plaintext
+}
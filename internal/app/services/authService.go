@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"starter/internal/app/constants"
+	"starter/internal/app/logging"
+	"starter/internal/app/models"
+	Repository "starter/internal/app/repository"
+	"starter/internal/app/utils"
+	"starter/internal/config"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	tokenType   = "Bearer"
+	tokenIssuer = "starter"
+)
+
+//go:generate mockery --name AuthService
+type AuthService interface {
+	Login(ctx context.Context, loginDto *models.LoginRequestDto) (*models.LoginResponseDto, *utils.ErrorMessage)
+	ValidateToken(ctx context.Context, token string) (*models.User, *utils.ErrorMessage)
+	PurgeExpiredSessions(ctx context.Context) *utils.ErrorMessage
+}
+
+type authHandler struct {
+	jwtKeys     config.Keyring
+	tokenTTL    time.Duration
+	userService UserService
+	userRepo    Repository.UserRepository
+	sessionRepo Repository.SessionRepository
+}
+
+func NewAuthService(userService UserService, userRepo Repository.UserRepository, sessionRepo Repository.SessionRepository,
+	authConfig config.AuthConfig) AuthService {
+	return &authHandler{
+		jwtKeys:     authConfig.JWTKeyring(),
+		tokenTTL:    authConfig.TokenTTL,
+		userService: userService,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+func (as *authHandler) Login(ctx context.Context, loginDto *models.LoginRequestDto) (*models.LoginResponseDto, *utils.ErrorMessage) {
+	if err := loginDto.Validate(); err != nil {
+		return nil, err
+	}
+	user, err := as.userService.GetUserCredentials(ctx, loginDto.UserEmailId)
+	if err != nil {
+		if err.StatusCode != http.StatusNotFound {
+			return nil, err
+		}
+		// Hash anyway so unknown emails take as long as wrong passwords
+		utils.HashPassword(loginDto.UserPassword, loginDto.UserEmailId)
+		return nil, &utils.ErrorMessage{StatusCode: http.StatusUnauthorized, Message: constants.WRONG_CREDENTIALS}
+	}
+	if !utils.VerifyPassword(loginDto.UserPassword, user.StoredSalt, user.EncryptedPassword) {
+		return nil, &utils.ErrorMessage{StatusCode: http.StatusUnauthorized, Message: constants.WRONG_CREDENTIALS}
+	}
+
+	sessionId, uuidErr := uuid.NewV7()
+	if uuidErr != nil {
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to generate session id: %v", uuidErr)
+		return nil, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: constants.TOKEN_GENERATE_FAIL}
+	}
+	now := time.Now().UTC()
+	session, err := as.sessionRepo.Create(ctx, &models.Session{
+		ID:         sessionId.String(),
+		UserID:     user.ID,
+		InsertedAt: now,
+		ExpiresAt:  now.Add(as.tokenTTL),
+	})
+	if err != nil {
+		logging.ForComponent(ctx, logComponent).Errorf("%s, user %d: %s", constants.USER_SESSION_FAILED_CREATE, user.ID, err.Message)
+		return nil, repositoryError(err, constants.USER_SESSION_FAILED_CREATE)
+	}
+
+	token, signErr := as.signToken(session, user)
+	if signErr != nil {
+		return nil, signErr
+	}
+	return &models.LoginResponseDto{
+		AccessToken: token,
+		TokenType:   tokenType,
+		ExpiresAt:   session.ExpiresAt,
+	}, nil
+}
+
+// signToken issues the access token of a session. Anyone holding a token can read its claims,
+// the user is identified by ID so that the email stays out of them.
+func (as *authHandler) signToken(session *models.Session, user *models.User) (string, *utils.ErrorMessage) {
+	claims := &models.AuthClaims{
+		SessionID: session.ID,
+		UserID:    user.ID,
+		UserRole:  user.UserRole,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   strconv.FormatInt(user.ID, 10),
+			IssuedAt:  jwt.NewNumericDate(session.InsertedAt),
+			ExpiresAt: jwt.NewNumericDate(session.ExpiresAt),
+			ID:        session.ID,
+		},
+	}
+	keyID, key := as.jwtKeys.Active()
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	unsigned.Header["kid"] = keyID
+	token, err := unsigned.SignedString(key)
+	if err != nil {
+		logging.Component(logComponent).Errorf("Failed to sign token: %v", err)
+		return "", &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: constants.TOKEN_SIGNING_FAILED}
+	}
+	return token, nil
+}
+
+// ValidateToken parses the access token, checks the backing session and returns the authenticated user.
+func (as *authHandler) ValidateToken(ctx context.Context, token string) (*models.User, *utils.ErrorMessage) {
+	claims := &models.AuthClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, as.verificationKey, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(tokenIssuer))
+	if err != nil {
+		logging.ForComponent(ctx, logComponent).Warnf("Rejected access token: %v", err)
+		if errors.Is(err, jwt.ErrTokenMalformed) {
+			return nil, &utils.ErrorMessage{StatusCode: http.StatusUnauthorized, Message: constants.PARSE_TOKEN_FAIL}
+		}
+		return nil, &utils.ErrorMessage{StatusCode: http.StatusUnauthorized, Message: constants.INVALID_TOKEN}
+	}
+	if len(claims.SessionID) == 0 {
+		return nil, &utils.ErrorMessage{StatusCode: http.StatusUnauthorized, Message: constants.INVALID_TOKEN}
+	}
+
+	session, sErr := as.sessionRepo.Get(ctx, claims.SessionID)
+	if sErr != nil {
+		if sErr.StatusCode == http.StatusNotFound {
+			return nil, &utils.ErrorMessage{StatusCode: http.StatusUnauthorized, Message: constants.INVALID_SESSION}
+		}
+		return nil, sErr
+	}
+	if session.UserID != claims.UserID || time.Now().After(session.ExpiresAt) {
+		return nil, &utils.ErrorMessage{StatusCode: http.StatusUnauthorized, Message: constants.INVALID_SESSION}
+	}
+
+	user, uErr := as.userRepo.GetUserByID(ctx, session.UserID)
+	if uErr != nil {
+		if uErr.StatusCode == http.StatusNotFound {
+			return nil, &utils.ErrorMessage{StatusCode: http.StatusUnauthorized, Message: constants.INVALID_SESSION}
+		}
+		return nil, uErr
+	}
+	return user, nil
+}
+
+// verificationKey picks the key named by the kid header, tokens signed before key IDs
+// were introduced are checked against the legacy key.
+func (as *authHandler) verificationKey(token *jwt.Token) (interface{}, error) {
+	keyID, _ := token.Header["kid"].(string)
+	if keyID == "" {
+		keyID = config.LegacyKeyID
+	}
+	key, ok := as.jwtKeys.Key(keyID)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", keyID)
+	}
+	return key, nil
+}
+
+// PurgeExpiredSessions deletes the sessions whose tokens can no longer be used.
+func (as *authHandler) PurgeExpiredSessions(ctx context.Context) *utils.ErrorMessage {
+	if err := as.sessionRepo.DeleteExpired(ctx, time.Now().UTC()); err != nil {
+		return repositoryError(err, constants.FAILED_TO_PURGE_SESSIONS)
+	}
+	return nil
+}
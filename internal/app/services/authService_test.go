@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"starter/internal/app/constants"
+	"starter/internal/app/models"
+	"starter/internal/app/repository/mocks"
+	"starter/internal/app/utils"
+	"starter/internal/config"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testKeys = config.Keyring{ActiveID: "k2", Keys: map[string][]byte{
+	"k1": []byte("an-older-signing-key-of-32-bytes"),
+	"k2": []byte("the-active-signing-key-32-bytes!"),
+}}
+
+func newTestAuthService(t *testing.T) (*authHandler, *mocks.UserRepository, *mocks.SessionRepository) {
+	userRepo, sessionRepo := mocks.NewUserRepository(t), mocks.NewSessionRepository(t)
+	return &authHandler{
+		jwtKeys:     testKeys,
+		tokenTTL:    time.Hour,
+		userService: &userHandler{userRepo: userRepo},
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+	}, userRepo, sessionRepo
+}
+
+// testUser holds the credentials of the password "correct horse".
+func testUser(t *testing.T) *models.User {
+	salt, err := utils.GenerateSalt()
+	require.NoError(t, err)
+	return &models.User{ID: 7, UserEmailId: "jane@example.com", UserRole: models.RoleTester,
+		StoredSalt: salt, EncryptedPassword: utils.HashPassword("correct horse", salt)}
+}
+
+// signTestToken signs claims for session s-1 of user 7 with the given method and key ID.
+func signTestToken(t *testing.T, method jwt.SigningMethod, keyID string, key interface{}, expiresAt time.Time) string {
+	claims := &models.AuthClaims{SessionID: "s-1", UserID: 7, UserRole: models.RoleTester,
+		RegisteredClaims: jwt.RegisteredClaims{Issuer: tokenIssuer, ExpiresAt: jwt.NewNumericDate(expiresAt)}}
+	unsigned := jwt.NewWithClaims(method, claims)
+	unsigned.Header["kid"] = keyID
+	token, err := unsigned.SignedString(key)
+	require.NoError(t, err)
+	return token
+}
+
+func TestLogin_Succeeds(t *testing.T) {
+	service, userRepo, sessionRepo := newTestAuthService(t)
+	user := testUser(t)
+	userRepo.On("Get", mock.Anything, user.UserEmailId).Return(user, nil)
+	sessionRepo.On("Create", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, session *models.Session) (*models.Session, *utils.ErrorMessage) {
+			return session, nil
+		})
+
+	login, err := service.Login(context.Background(), &models.LoginRequestDto{UserEmailId: user.UserEmailId, UserPassword: "correct horse"})
+	require.Nil(t, err)
+	assert.Equal(t, tokenType, login.TokenType)
+
+	claims := &models.AuthClaims{}
+	_, _, parseErr := jwt.NewParser().ParseUnverified(login.AccessToken, claims)
+	require.NoError(t, parseErr)
+	assert.Equal(t, "7", claims.Subject)
+	payload, decodeErr := base64.RawURLEncoding.DecodeString(strings.Split(login.AccessToken, ".")[1])
+	require.NoError(t, decodeErr)
+	assert.NotContains(t, string(payload), user.UserEmailId, "claims are readable by anyone holding the token")
+}
+
+func TestLogin_RejectsWrongCredentials(t *testing.T) {
+	service, userRepo, _ := newTestAuthService(t)
+	user := testUser(t)
+	userRepo.On("Get", mock.Anything, user.UserEmailId).Return(user, nil)
+	userRepo.On("Get", mock.Anything, "nobody@example.com").
+		Return(nil, &utils.ErrorMessage{StatusCode: http.StatusNotFound, Message: "not found"})
+
+	for _, login := range []*models.LoginRequestDto{
+		{UserEmailId: user.UserEmailId, UserPassword: "wrong horse"},
+		{UserEmailId: "nobody@example.com", UserPassword: "correct horse"},
+	} {
+		_, err := service.Login(context.Background(), login)
+		require.NotNil(t, err, login.UserEmailId)
+		assert.Equal(t, http.StatusUnauthorized, err.StatusCode, login.UserEmailId)
+		assert.Equal(t, constants.WRONG_CREDENTIALS, err.Message, "unknown emails and wrong passwords look the same")
+	}
+}
+
+func TestValidateToken_AcceptsAnOlderKey(t *testing.T) {
+	service, userRepo, sessionRepo := newTestAuthService(t)
+	user := testUser(t)
+	sessionRepo.On("Get", mock.Anything, "s-1").Return(&models.Session{ID: "s-1", UserID: 7, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	userRepo.On("GetUserByID", mock.Anything, int64(7)).Return(user, nil)
+
+	authenticated, err := service.ValidateToken(context.Background(),
+		signTestToken(t, jwt.SigningMethodHS256, "k1", testKeys.Keys["k1"], time.Now().Add(time.Hour)))
+	require.Nil(t, err)
+	assert.Equal(t, user.ID, authenticated.ID)
+}
+
+func TestValidateToken_RejectsInvalidSessions(t *testing.T) {
+	cases := []struct {
+		name    string
+		session *models.Session
+		err     *utils.ErrorMessage
+	}{
+		{"revoked", nil, &utils.ErrorMessage{StatusCode: http.StatusNotFound}},
+		{"expired", &models.Session{ID: "s-1", UserID: 7, ExpiresAt: time.Now().Add(-time.Minute)}, nil},
+		{"of another user", &models.Session{ID: "s-1", UserID: 8, ExpiresAt: time.Now().Add(time.Hour)}, nil},
+	}
+	for _, tt := range cases {
+		service, _, sessionRepo := newTestAuthService(t)
+		sessionRepo.On("Get", mock.Anything, "s-1").Return(tt.session, tt.err)
+
+		_, err := service.ValidateToken(context.Background(),
+			signTestToken(t, jwt.SigningMethodHS256, "k2", testKeys.Keys["k2"], time.Now().Add(time.Hour)))
+		require.NotNil(t, err, tt.name)
+		assert.Equal(t, http.StatusUnauthorized, err.StatusCode, tt.name)
+		assert.Equal(t, constants.INVALID_SESSION, err.Message, tt.name)
+	}
+}
+
+func TestValidateToken_RejectsInvalidTokens(t *testing.T) {
+	service, _, _ := newTestAuthService(t)
+	inAnHour := time.Now().Add(time.Hour)
+	cases := []struct {
+		name    string
+		token   string
+		message string
+	}{
+		{"expired", signTestToken(t, jwt.SigningMethodHS256, "k2", testKeys.Keys["k2"], time.Now().Add(-time.Minute)), constants.INVALID_TOKEN},
+		{"unknown kid", signTestToken(t, jwt.SigningMethodHS256, "k9", testKeys.Keys["k2"], inAnHour), constants.INVALID_TOKEN},
+		{"HS512", signTestToken(t, jwt.SigningMethodHS512, "k2", testKeys.Keys["k2"], inAnHour), constants.INVALID_TOKEN},
+		{"none", signTestToken(t, jwt.SigningMethodNone, "k2", jwt.UnsafeAllowNoneSignatureType, inAnHour), constants.INVALID_TOKEN},
+		{"wrong key", signTestToken(t, jwt.SigningMethodHS256, "k2", []byte("a-forged-key"), inAnHour), constants.INVALID_TOKEN},
+		{"malformed", "not.a-token", constants.PARSE_TOKEN_FAIL},
+	}
+	for _, tt := range cases {
+		_, err := service.ValidateToken(context.Background(), tt.token)
+		require.NotNil(t, err, tt.name)
+		assert.Equal(t, http.StatusUnauthorized, err.StatusCode, tt.name)
+		assert.Equal(t, tt.message, err.Message, tt.name)
+	}
+}
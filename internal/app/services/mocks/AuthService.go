@@ -0,0 +1,115 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "starter/internal/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	utils "starter/internal/app/utils"
+)
+
+// AuthService is an autogenerated mock type for the AuthService type
+type AuthService struct {
+	mock.Mock
+}
+
+// Login provides a mock function with given fields: ctx, loginDto
+func (_m *AuthService) Login(ctx context.Context, loginDto *models.LoginRequestDto) (*models.LoginResponseDto, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, loginDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 *models.LoginResponseDto
+	var r1 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, *models.LoginRequestDto) (*models.LoginResponseDto, *utils.ErrorMessage)); ok {
+		return rf(ctx, loginDto)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.LoginRequestDto) *models.LoginResponseDto); ok {
+		r0 = rf(ctx, loginDto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.LoginResponseDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.LoginRequestDto) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, loginDto)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0, r1
+}
+
+// PurgeExpiredSessions provides a mock function with given fields: ctx
+func (_m *AuthService) PurgeExpiredSessions(ctx context.Context) *utils.ErrorMessage {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeExpiredSessions")
+	}
+
+	var r0 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context) *utils.ErrorMessage); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0
+}
+
+// ValidateToken provides a mock function with given fields: ctx, token
+func (_m *AuthService) ValidateToken(ctx context.Context, token string) (*models.User, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateToken")
+	}
+
+	var r0 *models.User
+	var r1 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, *utils.ErrorMessage)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewAuthService creates a new instance of AuthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuthService {
+	mock := &AuthService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	http "net/http"
 
 	mock "github.com/stretchr/testify/mock"
@@ -45,17 +46,47 @@ func (_m *RestCaller) Get(url string) (*http.Response, error) {
 	return r0, r1
 }
 
-// MakeRestCallToPartner provides a mock function with given fields: url, params
-func (_m *RestCaller) MakeRestCallToPartner(url string, params string) *utils.ErrorMessage {
-	ret := _m.Called(url, params)
+// GetWithContext provides a mock function with given fields: ctx, url
+func (_m *RestCaller) GetWithContext(ctx context.Context, url string) (*http.Response, error) {
+	ret := _m.Called(ctx, url)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithContext")
+	}
+
+	var r0 *http.Response
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*http.Response, error)); ok {
+		return rf(ctx, url)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *http.Response); ok {
+		r0 = rf(ctx, url)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*http.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, url)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MakeRestCallToPartner provides a mock function with given fields: ctx, url, params
+func (_m *RestCaller) MakeRestCallToPartner(ctx context.Context, url string, params string) *utils.ErrorMessage {
+	ret := _m.Called(ctx, url, params)
 
 	if len(ret) == 0 {
 		panic("no return value specified for MakeRestCallToPartner")
 	}
 
 	var r0 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(string, string) *utils.ErrorMessage); ok {
-		r0 = rf(url, params)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, url, params)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*utils.ErrorMessage)
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	models "starter/internal/app/models"
 
 	mock "github.com/stretchr/testify/mock"
@@ -15,29 +16,229 @@ type UserService struct {
 	mock.Mock
 }
 
-// GetUserByEmail provides a mock function with given fields: emailId
-func (_m *UserService) GetUserByEmail(emailId string) (*models.User, *utils.ErrorMessage) {
-	ret := _m.Called(emailId)
+// ChangePassword provides a mock function with given fields: ctx, currentUser, passwordDto
+func (_m *UserService) ChangePassword(ctx context.Context, currentUser *models.User, passwordDto *models.PasswordUpdateDto) *utils.ErrorMessage {
+	ret := _m.Called(ctx, currentUser, passwordDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, *models.PasswordUpdateDto) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, currentUser, passwordDto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0
+}
+
+// CreateUser provides a mock function with given fields: ctx, userDto
+func (_m *UserService) CreateUser(ctx context.Context, userDto *models.UserRequestDto) (*models.UserResponseDto, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, userDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 *models.UserResponseDto
+	var r1 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, *models.UserRequestDto) (*models.UserResponseDto, *utils.ErrorMessage)); ok {
+		return rf(ctx, userDto)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.UserRequestDto) *models.UserResponseDto); ok {
+		r0 = rf(ctx, userDto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserResponseDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.UserRequestDto) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, userDto)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeleteUser provides a mock function with given fields: ctx, currentUser, emailId
+func (_m *UserService) DeleteUser(ctx context.Context, currentUser *models.User, emailId string) *utils.ErrorMessage {
+	ret := _m.Called(ctx, currentUser, emailId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, string) *utils.ErrorMessage); ok {
+		r0 = rf(ctx, currentUser, emailId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0
+}
+
+// GetUserByEmail provides a mock function with given fields: ctx, emailId
+func (_m *UserService) GetUserByEmail(ctx context.Context, emailId string) (*models.UserResponseDto, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, emailId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserByEmail")
 	}
 
+	var r0 *models.UserResponseDto
+	var r1 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.UserResponseDto, *utils.ErrorMessage)); ok {
+		return rf(ctx, emailId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.UserResponseDto); ok {
+		r0 = rf(ctx, emailId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserResponseDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, emailId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetUserByID provides a mock function with given fields: ctx, id
+func (_m *UserService) GetUserByID(ctx context.Context, id int64) (*models.UserResponseDto, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 *models.UserResponseDto
+	var r1 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*models.UserResponseDto, *utils.ErrorMessage)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.UserResponseDto); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserResponseDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetUserCredentials provides a mock function with given fields: ctx, emailId
+func (_m *UserService) GetUserCredentials(ctx context.Context, emailId string) (*models.User, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, emailId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserCredentials")
+	}
+
 	var r0 *models.User
 	var r1 *utils.ErrorMessage
-	if rf, ok := ret.Get(0).(func(string) (*models.User, *utils.ErrorMessage)); ok {
-		return rf(emailId)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, *utils.ErrorMessage)); ok {
+		return rf(ctx, emailId)
 	}
-	if rf, ok := ret.Get(0).(func(string) *models.User); ok {
-		r0 = rf(emailId)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, emailId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) *utils.ErrorMessage); ok {
-		r1 = rf(emailId)
+	if rf, ok := ret.Get(1).(func(context.Context, string) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, emailId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListUsers provides a mock function with given fields: ctx, pagination, filter
+func (_m *UserService) ListUsers(ctx context.Context, pagination *utils.Pagination, filter *models.UserFilter) (*utils.Pagination, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, pagination, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 *utils.Pagination
+	var r1 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, *utils.Pagination, *models.UserFilter) (*utils.Pagination, *utils.ErrorMessage)); ok {
+		return rf(ctx, pagination, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *utils.Pagination, *models.UserFilter) *utils.Pagination); ok {
+		r0 = rf(ctx, pagination, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*utils.Pagination)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *utils.Pagination, *models.UserFilter) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, pagination, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*utils.ErrorMessage)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateSelf provides a mock function with given fields: ctx, currentUser, updateDto
+func (_m *UserService) UpdateSelf(ctx context.Context, currentUser *models.User, updateDto *models.UserUpdateDto) (*models.UserResponseDto, *utils.ErrorMessage) {
+	ret := _m.Called(ctx, currentUser, updateDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSelf")
+	}
+
+	var r0 *models.UserResponseDto
+	var r1 *utils.ErrorMessage
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, *models.UserUpdateDto) (*models.UserResponseDto, *utils.ErrorMessage)); ok {
+		return rf(ctx, currentUser, updateDto)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, *models.UserUpdateDto) *models.UserResponseDto); ok {
+		r0 = rf(ctx, currentUser, updateDto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserResponseDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, *models.UserUpdateDto) *utils.ErrorMessage); ok {
+		r1 = rf(ctx, currentUser, updateDto)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.ErrorMessage)
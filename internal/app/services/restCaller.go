@@ -1,19 +1,31 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"starter/internal/app/correlation"
+	"starter/internal/app/logging"
+	"starter/internal/app/metrics"
+	"starter/internal/app/tracing"
 	"starter/internal/app/utils"
+	"strings"
 	"time"
 
-	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	maxRetries     = 5
 	initialBackoff = 1 * time.Second
 	clientTimeout  = 30 * time.Second
+	// maxLoggedBody bounds the part of a partner response that is logged
+	maxLoggedBody = 1024
 )
 
 // RestCaller defines the interface for making REST calls.
@@ -21,7 +33,8 @@ const (
 //go:generate mockery --name RestCaller
 type RestCaller interface {
 	Get(url string) (*http.Response, error)
-	MakeRestCallToPartner(url string, params string) *utils.ErrorMessage
+	GetWithContext(ctx context.Context, url string) (*http.Response, error)
+	MakeRestCallToPartner(ctx context.Context, url string, params string) *utils.ErrorMessage
 }
 
 // DefaultRestCaller is the default implementation of RestCaller using http.Client.
@@ -34,25 +47,97 @@ func NewDefaultRestCaller() RestCaller {
 	return &restCaller{
 		client: &http.Client{
 			Timeout: clientTimeout,
+			// Every attempt gets a client span and forwards its traceparent, the span records the URL
+			// without its query
+			Transport: hideQuery{next: otelhttp.NewTransport(restoreQuery{next: http.DefaultTransport})},
 		},
 	}
 }
 
 // Get makes a GET request using the underlying http.Client.
 func (rc *restCaller) Get(url string) (*http.Response, error) {
-	return rc.client.Get(url)
+	resp, err := rc.client.Get(url)
+	return resp, withoutQuery(err)
 }
 
-// MakeRestCallToPartner makes a REST call to Partner using the provided RestCaller.
-func (rc *restCaller) MakeRestCallToPartner(url string, params string) *utils.ErrorMessage {
+// GetWithContext makes a GET request that is abandoned once ctx is done. The request ID and
+// trace context of ctx are forwarded so that the callee can correlate its logs with ours.
+func (rc *restCaller) GetWithContext(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	correlation.Inject(ctx, req.Header)
+	resp, err := rc.client.Do(req)
+	return resp, withoutQuery(err)
+}
+
+// queryKey carries the query of a request past the client span, from hideQuery to restoreQuery.
+// Partner queries may carry credentials, which must not reach the traces.
+type queryKey struct{}
+
+// hideQuery removes the query from the request seen by the transport it wraps.
+type hideQuery struct {
+	next http.RoundTripper
+}
+
+func (h hideQuery) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.RawQuery == "" {
+		return h.next.RoundTrip(req)
+	}
+	hidden := req.Clone(context.WithValue(req.Context(), queryKey{}, req.URL.RawQuery))
+	hidden.URL.RawQuery = ""
+	return h.next.RoundTrip(hidden)
+}
+
+// restoreQuery puts back the query removed by hideQuery before the request is sent.
+type restoreQuery struct {
+	next http.RoundTripper
+}
+
+func (r restoreQuery) RoundTrip(req *http.Request) (*http.Response, error) {
+	query, ok := req.Context().Value(queryKey{}).(string)
+	if !ok {
+		return r.next.RoundTrip(req)
+	}
+	restored := req.Clone(req.Context())
+	restored.URL.RawQuery = query
+	return r.next.RoundTrip(restored)
+}
+
+// withoutQuery strips the query from the URL that http.Client adds to its errors.
+func withoutQuery(err error) error {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+	stripped := *urlErr
+	stripped.URL, _, _ = strings.Cut(urlErr.URL, "?")
+	return &stripped
+}
+
+// MakeRestCallToPartner makes a REST call to Partner using the provided RestCaller. The call is
+// traced as one span holding the span of each attempt, retries are recorded as events and counted.
+func (rc *restCaller) MakeRestCallToPartner(ctx context.Context, url string, params string) (errMsg *utils.ErrorMessage) {
+	ctx, span := tracing.Tracer().Start(ctx, "partner call")
+	start := time.Now()
+	defer func() {
+		if errMsg != nil {
+			span.SetStatus(codes.Error, errMsg.Message)
+		}
+		span.End()
+		metrics.ObservePartnerCall(time.Since(start), errMsg != nil)
+	}()
 	retries := 0
 	var body []byte
+	// The query may carry credentials of the partner, only the URL without it is logged
+	logging.ForComponent(ctx, logComponent).Debugf("Partner URL Formed: %v", url)
 	url = url + "?" + params
-	logrus.Debugf("Partner URL Formed: %v", url)
 	for {
-		resp, restErr := rc.Get(url)
+		resp, restErr := rc.GetWithContext(ctx, url)
 		if restErr != nil {
-			if retries >= maxRetries {
+			span.RecordError(restErr, trace.WithAttributes(attribute.Int("app.attempt", retries+1)))
+			if retries >= maxRetries || ctx.Err() != nil {
 				statusCode := 0
 				if resp != nil {
 					statusCode = resp.StatusCode
@@ -63,11 +148,13 @@ func (rc *restCaller) MakeRestCallToPartner(url string, params string) *utils.Er
 				}
 			}
 			retries++
+			metrics.PartnerRetry()
+			span.AddEvent("retry", trace.WithAttributes(attribute.Int("app.attempt", retries+1)))
 			time.Sleep(initialBackoff * time.Duration(retries))
 			continue
 		}
 		statusCode := resp.StatusCode
-		logrus.Debug("trying to read response body if any")
+		logging.ForComponent(ctx, logComponent).Debug("trying to read response body if any")
 		if resp.Body != nil {
 			defer resp.Body.Close()
 			body, readErr := io.ReadAll(resp.Body)
@@ -77,7 +164,7 @@ func (rc *restCaller) MakeRestCallToPartner(url string, params string) *utils.Er
 					StatusCode: statusCode,
 				}
 			}
-			logrus.Infof("Printing any response found from Partner: %v", string(body))
+			logging.ForComponent(ctx, logComponent).Debugf("Printing any response found from Partner: %v", truncate(body, maxLoggedBody))
 		}
 		if statusCode != http.StatusOK {
 			return &utils.ErrorMessage{
@@ -85,8 +172,16 @@ func (rc *restCaller) MakeRestCallToPartner(url string, params string) *utils.Er
 				StatusCode: statusCode,
 			}
 		}
-		logrus.Info("Successfully contacted Partner To run the scripts")
+		logging.ForComponent(ctx, logComponent).Info("Successfully contacted Partner To run the scripts")
 		break
 	}
 	return nil
 }
+
+// truncate returns at most limit bytes of body, noting how much was left out.
+func truncate(body []byte, limit int) string {
+	if len(body) <= limit {
+		return string(body)
+	}
+	return fmt.Sprintf("%s... (%d more bytes)", body[:limit], len(body)-limit)
+}
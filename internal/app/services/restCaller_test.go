@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// assertNoSecretInSpans checks the attributes, events and status of every span.
+func assertNoSecretInSpans(t *testing.T, recorder *tracetest.SpanRecorder, secret string) {
+	spans := recorder.Ended()
+	require.NotEmpty(t, spans)
+	for _, span := range spans {
+		assert.NotContains(t, span.Status().Description, secret, span.Name())
+		for _, kv := range span.Attributes() {
+			assert.NotContains(t, kv.Value.Emit(), secret, "%s %s", span.Name(), kv.Key)
+		}
+		for _, event := range span.Events() {
+			for _, kv := range event.Attributes {
+				assert.NotContains(t, kv.Value.Emit(), secret, "%s %s", span.Name(), kv.Key)
+			}
+		}
+	}
+}
+
+func TestMakeRestCallToPartner_KeepsTheQueryOutOfTheTraces(t *testing.T) {
+	recorder := recordSpans(t)
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	errMsg := NewDefaultRestCaller().MakeRestCallToPartner(context.Background(), server.URL+"/run", "token=partner-secret")
+	assert.Nil(t, errMsg)
+	assert.Equal(t, "token=partner-secret", received)
+	assertNoSecretInSpans(t, recorder, "partner-secret")
+}
+
+func TestGetWithContext_ErrorsLeaveTheQueryOut(t *testing.T) {
+	recorder := recordSpans(t)
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	_, err := NewDefaultRestCaller().GetWithContext(context.Background(), server.URL+"/run?token=partner-secret")
+	require.Error(t, err)
+	assert.False(t, strings.Contains(err.Error(), "partner-secret"), err.Error())
+	assert.Contains(t, err.Error(), "/run")
+	assertNoSecretInSpans(t, recorder, "partner-secret")
+}
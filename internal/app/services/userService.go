@@ -1,31 +1,217 @@
 package services
 
 import (
+	"context"
 	"net/http"
+	"starter/internal/app/constants"
+	"starter/internal/app/logging"
 	"starter/internal/app/models"
 	Repository "starter/internal/app/repository"
 	"starter/internal/app/utils"
+	"time"
 )
 
 //go:generate mockery --name UserService
 type UserService interface {
-	GetUserByEmail(emailId string) (*models.User, *utils.ErrorMessage)
+	GetUserByEmail(ctx context.Context, emailId string) (*models.UserResponseDto, *utils.ErrorMessage)
+	GetUserCredentials(ctx context.Context, emailId string) (*models.User, *utils.ErrorMessage)
+	GetUserByID(ctx context.Context, id int64) (*models.UserResponseDto, *utils.ErrorMessage)
+	ListUsers(ctx context.Context, pagination *utils.Pagination, filter *models.UserFilter) (*utils.Pagination, *utils.ErrorMessage)
+	CreateUser(ctx context.Context, userDto *models.UserRequestDto) (*models.UserResponseDto, *utils.ErrorMessage)
+	UpdateSelf(ctx context.Context, currentUser *models.User, updateDto *models.UserUpdateDto) (*models.UserResponseDto, *utils.ErrorMessage)
+	ChangePassword(ctx context.Context, currentUser *models.User, passwordDto *models.PasswordUpdateDto) *utils.ErrorMessage
+	DeleteUser(ctx context.Context, currentUser *models.User, emailId string) *utils.ErrorMessage
 }
 
+// logComponent tags what the services log, its level can be set on its own at /internal/log
+var logComponent = logging.RegisterComponent("services")
+
 type userHandler struct {
-	aesKey   string
-	userRepo Repository.UserRepository
+	crudRepo    Repository.CRUDRepository
+	userRepo    Repository.UserRepository
+	sessionRepo Repository.SessionRepository
+}
+
+// repositoryError hides repository failures behind a generic message, except for timed out
+// and cancelled queries which keep their own status so callers can tell them apart.
+func repositoryError(err *utils.ErrorMessage, message string) *utils.ErrorMessage {
+	switch err.StatusCode {
+	case http.StatusGatewayTimeout, utils.StatusClientClosedRequest:
+		return err
+	}
+	return &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: message}
 }
 
-func NewUserService(userRepo Repository.UserRepository) UserService {
-	aesKey := utils.GetEnvAsString("AES_KEY", "1234567812345678")
-	return &userHandler{userRepo: userRepo, aesKey: aesKey}
+func NewUserService(crudRepo Repository.CRUDRepository, userRepo Repository.UserRepository,
+	sessionRepo Repository.SessionRepository) UserService {
+	return &userHandler{crudRepo: crudRepo, userRepo: userRepo, sessionRepo: sessionRepo}
 }
 
-func (us *userHandler) GetUserByEmail(emailId string) (*models.User, *utils.ErrorMessage) {
-	user, err := us.userRepo.Get(emailId)
+// GetUserByEmail returns the public profile of the user, safe to send to API callers.
+func (us *userHandler) GetUserByEmail(ctx context.Context, emailId string) (*models.UserResponseDto, *utils.ErrorMessage) {
+	user, err := us.userRepo.GetProfile(ctx, emailId)
 	if err != nil {
-		return nil, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: "Failed to fetch user details, please try again."}
+		if err.StatusCode == http.StatusNotFound {
+			return nil, err
+		}
+		return nil, repositoryError(err, constants.FAILED_TO_FETCH_USER)
+	}
+	return user.ToResponseDto(), nil
+}
+
+// GetUserCredentials returns the user including the password hash and salt.
+// It is meant for authentication only and must never be used to build a response.
+func (us *userHandler) GetUserCredentials(ctx context.Context, emailId string) (*models.User, *utils.ErrorMessage) {
+	user, err := us.userRepo.Get(ctx, emailId)
+	if err != nil {
+		if err.StatusCode == http.StatusNotFound {
+			return nil, err
+		}
+		return nil, repositoryError(err, constants.FAILED_TO_FETCH_USER)
 	}
 	return user, nil
 }
+
+func (us *userHandler) CreateUser(ctx context.Context, userDto *models.UserRequestDto) (*models.UserResponseDto, *utils.ErrorMessage) {
+	if err := userDto.Validate(); err != nil {
+		return nil, err
+	}
+	_, err := us.userRepo.GetProfile(ctx, userDto.UserEmailId)
+	if err == nil {
+		return nil, &utils.ErrorMessage{StatusCode: http.StatusConflict, Message: constants.USER_ALREADY_EXISTS}
+	}
+	if err.StatusCode != http.StatusNotFound {
+		return nil, repositoryError(err, constants.FAILED_TO_CREATE_USER)
+	}
+
+	salt, saltErr := utils.GenerateSalt()
+	if saltErr != nil {
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to generate salt: %v", saltErr)
+		return nil, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: constants.PASSWORD_HASH_FAILED}
+	}
+	now := time.Now().UTC()
+	user := &models.User{
+		UserEmailId:       userDto.UserEmailId,
+		EncryptedPassword: utils.HashPassword(userDto.UserPassword, salt),
+		StoredSalt:        salt,
+		InsertedAt:        now,
+		UpdatedAt:         now,
+		UserDisplayName:   userDto.UserDisplayName,
+		UserFirstName:     userDto.UserFirstName,
+		UserLastName:      userDto.UserLastName,
+		UserRole:          userDto.UserRole,
+	}
+	user, err = us.userRepo.Create(ctx, user)
+	if err != nil {
+		if err.StatusCode == http.StatusConflict {
+			return nil, &utils.ErrorMessage{StatusCode: http.StatusConflict, Message: constants.USER_ALREADY_EXISTS}
+		}
+		return nil, repositoryError(err, constants.FAILED_TO_CREATE_USER)
+	}
+	return user.ToResponseDto(), nil
+}
+
+func (us *userHandler) GetUserByID(ctx context.Context, id int64) (*models.UserResponseDto, *utils.ErrorMessage) {
+	if id <= 0 {
+		return nil, &utils.ErrorMessage{StatusCode: http.StatusBadRequest, Message: constants.INVALID_ID}
+	}
+	user, err := us.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		if err.StatusCode == http.StatusNotFound {
+			return nil, err
+		}
+		return nil, repositoryError(err, constants.FAILED_TO_FETCH_USER)
+	}
+	return user.ToResponseDto(), nil
+}
+
+func (us *userHandler) ListUsers(ctx context.Context, pagination *utils.Pagination, filter *models.UserFilter) (*utils.Pagination, *utils.ErrorMessage) {
+	page, err := us.userRepo.ListUsers(ctx, pagination, filter)
+	if err != nil {
+		return nil, repositoryError(err, constants.FAILED_TO_FETCH_USER)
+	}
+	rows, _ := page.Rows.([]interface{})
+	page.Rows = models.ToUserResponseDtos(rows)
+	return page, nil
+}
+
+// UpdateSelf updates the profile of the authenticated user, including a change of email.
+func (us *userHandler) UpdateSelf(ctx context.Context, currentUser *models.User, updateDto *models.UserUpdateDto) (*models.UserResponseDto, *utils.ErrorMessage) {
+	if err := updateDto.Validate(); err != nil {
+		return nil, err
+	}
+	// Emails are looked up case-insensitively, the owner of the email decides whether it is taken
+	existing, err := us.userRepo.GetProfile(ctx, updateDto.UserEmailId)
+	if err == nil && existing.ID != currentUser.ID {
+		return nil, &utils.ErrorMessage{StatusCode: http.StatusConflict, Message: constants.USER_ALREADY_EXISTS}
+	}
+	if err != nil && err.StatusCode != http.StatusNotFound {
+		return nil, repositoryError(err, constants.FAILED_TO_UPDATE_USER)
+	}
+	user := &models.User{
+		UserEmailId:     updateDto.UserEmailId,
+		UserDisplayName: updateDto.UserDisplayName,
+		UserFirstName:   updateDto.UserFirstName,
+		UserLastName:    updateDto.UserLastName,
+	}
+	if err := us.userRepo.UpdateUserSelfDetails(ctx, currentUser.UserEmailId, user); err != nil {
+		if err.StatusCode == http.StatusConflict {
+			return nil, &utils.ErrorMessage{StatusCode: http.StatusConflict, Message: constants.USER_ALREADY_EXISTS}
+		}
+		return nil, repositoryError(err, constants.FAILED_TO_UPDATE_USER)
+	}
+	return us.GetUserByEmail(ctx, updateDto.UserEmailId)
+}
+
+// ChangePassword checks the old password and stores the new one under a freshly generated salt.
+func (us *userHandler) ChangePassword(ctx context.Context, currentUser *models.User, passwordDto *models.PasswordUpdateDto) *utils.ErrorMessage {
+	if err := passwordDto.Validate(); err != nil {
+		return err
+	}
+	user, err := us.GetUserCredentials(ctx, currentUser.UserEmailId)
+	if err != nil {
+		return err
+	}
+	if !utils.VerifyPassword(passwordDto.OldPassword, user.StoredSalt, user.EncryptedPassword) {
+		return &utils.ErrorMessage{StatusCode: http.StatusBadRequest, Message: constants.OLD_PASSWORD_MISMATCH}
+	}
+	salt, saltErr := utils.GenerateSalt()
+	if saltErr != nil {
+		logging.ForComponent(ctx, logComponent).Errorf("Failed to generate salt: %v", saltErr)
+		return &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: constants.PASSWORD_HASH_FAILED}
+	}
+	// Store the new password and revoke every existing session together, so a stolen
+	// token cannot outlive the password change
+	err = us.crudRepo.WithTransaction(ctx, func(txRepo Repository.CRUDRepository) *utils.ErrorMessage {
+		hashedPass := utils.HashPassword(passwordDto.NewPassword, salt)
+		if err := us.userRepo.WithTx(txRepo).UpdatePassword(ctx, user.UserEmailId, hashedPass, salt); err != nil {
+			return err
+		}
+		return us.sessionRepo.WithTx(txRepo).DeleteByUserID(ctx, user.ID)
+	})
+	if err != nil {
+		return repositoryError(err, constants.FAILED_TO_UPDATE_USER)
+	}
+	return nil
+}
+
+func (us *userHandler) DeleteUser(ctx context.Context, currentUser *models.User, emailId string) *utils.ErrorMessage {
+	user, err := us.GetUserByEmail(ctx, emailId)
+	if err != nil {
+		return err
+	}
+	// Compared by ID, the lookup above matches the email whatever its case
+	if user.UserId == currentUser.ID {
+		return &utils.ErrorMessage{StatusCode: http.StatusBadRequest, Message: constants.CANNOT_DELETE_SELF}
+	}
+	err = us.crudRepo.WithTransaction(ctx, func(txRepo Repository.CRUDRepository) *utils.ErrorMessage {
+		if err := us.sessionRepo.WithTx(txRepo).DeleteByUserID(ctx, user.UserId); err != nil {
+			return err
+		}
+		return us.userRepo.WithTx(txRepo).Delete(ctx, emailId)
+	})
+	if err != nil {
+		return repositoryError(err, constants.FAILED_TO_DELETE_USER)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"starter/internal/app/constants"
+	"starter/internal/app/models"
+	Repository "starter/internal/app/repository"
+	"starter/internal/app/repository/mocks"
+	"starter/internal/app/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestUserService(t *testing.T) (*userHandler, *mocks.CRUDRepository, *mocks.UserRepository, *mocks.SessionRepository) {
+	crudRepo, userRepo, sessionRepo := mocks.NewCRUDRepository(t), mocks.NewUserRepository(t), mocks.NewSessionRepository(t)
+	return &userHandler{crudRepo: crudRepo, userRepo: userRepo, sessionRepo: sessionRepo}, crudRepo, userRepo, sessionRepo
+}
+
+var admin = &models.User{ID: 1, UserEmailId: "admin@example.com", UserRole: models.RoleAdmin}
+
+func TestDeleteUser_RefusesSelfWhateverTheCase(t *testing.T) {
+	service, _, userRepo, _ := newTestUserService(t)
+	userRepo.On("GetProfile", mock.Anything, "Admin@Example.com").Return(admin, nil)
+
+	err := service.DeleteUser(context.Background(), admin, "Admin@Example.com")
+	assert.Equal(t, http.StatusBadRequest, err.StatusCode)
+	assert.Equal(t, constants.CANNOT_DELETE_SELF, err.Message)
+}
+
+func TestUpdateSelf_ChangingTheCaseOfTheEmailIsNoConflict(t *testing.T) {
+	service, _, userRepo, _ := newTestUserService(t)
+	update := &models.UserUpdateDto{UserEmailId: "Admin@example.com", UserFirstName: "Ada", UserLastName: "Admin", UserDisplayName: "ada"}
+	userRepo.On("GetProfile", mock.Anything, "Admin@example.com").Return(admin, nil)
+	userRepo.On("UpdateUserSelfDetails", mock.Anything, admin.UserEmailId, mock.Anything).Return(nil)
+
+	user, err := service.UpdateSelf(context.Background(), admin, update)
+	assert.Nil(t, err)
+	assert.Equal(t, admin.ID, user.UserId)
+}
+
+// runTransactions makes WithTransaction run its unit of work against crudRepo, handing the
+// transactional repositories back.
+func runTransactions(crudRepo *mocks.CRUDRepository, userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+	crudRepo.On("WithTransaction", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, fn Repository.TxFunc) *utils.ErrorMessage { return fn(crudRepo) })
+	userRepo.On("WithTx", crudRepo).Return(userRepo).Maybe()
+	sessionRepo.On("WithTx", crudRepo).Return(sessionRepo).Maybe()
+}
+
+func TestGetUserByID(t *testing.T) {
+	service, _, userRepo, _ := newTestUserService(t)
+	userRepo.On("GetUserByID", mock.Anything, int64(1)).Return(admin, nil)
+	userRepo.On("GetUserByID", mock.Anything, int64(2)).
+		Return(nil, &utils.ErrorMessage{StatusCode: http.StatusNotFound, Message: "No user found"})
+	userRepo.On("GetUserByID", mock.Anything, int64(3)).
+		Return(nil, &utils.ErrorMessage{StatusCode: http.StatusInternalServerError, Message: "pq: relation does not exist"})
+
+	user, err := service.GetUserByID(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, admin.UserEmailId, user.UserEmailId)
+
+	_, err = service.GetUserByID(context.Background(), 0)
+	assert.Equal(t, http.StatusBadRequest, err.StatusCode)
+	_, err = service.GetUserByID(context.Background(), 2)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+	_, err = service.GetUserByID(context.Background(), 3)
+	assert.Equal(t, constants.FAILED_TO_FETCH_USER, err.Message, "database errors stay internal")
+}
+
+func TestListUsers(t *testing.T) {
+	service, _, userRepo, _ := newTestUserService(t)
+	page := &utils.Pagination{Limit: 10, Page: 1, Rows: []interface{}{admin}}
+	userRepo.On("ListUsers", mock.Anything, mock.Anything, mock.Anything).Return(page, nil).Once()
+
+	listed, err := service.ListUsers(context.Background(), &utils.Pagination{}, &models.UserFilter{})
+	assert.Nil(t, err)
+	assert.Equal(t, []*models.UserResponseDto{admin.ToResponseDto()}, listed.Rows)
+
+	userRepo.On("ListUsers", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, &utils.ErrorMessage{StatusCode: http.StatusGatewayTimeout, Message: "timed out"})
+	_, err = service.ListUsers(context.Background(), &utils.Pagination{}, &models.UserFilter{})
+	assert.Equal(t, http.StatusGatewayTimeout, err.StatusCode, "timeouts keep their status")
+}
+
+func TestUpdateSelf_Conflicts(t *testing.T) {
+	service, _, userRepo, _ := newTestUserService(t)
+	other := &models.User{ID: 2, UserEmailId: "other@example.com"}
+	userRepo.On("GetProfile", mock.Anything, "other@example.com").Return(other, nil)
+	userRepo.On("GetProfile", mock.Anything, "new@example.com").
+		Return(nil, &utils.ErrorMessage{StatusCode: http.StatusNotFound})
+	// Taken by someone else between the lookup and the update
+	userRepo.On("UpdateUserSelfDetails", mock.Anything, admin.UserEmailId, mock.Anything).
+		Return(&utils.ErrorMessage{StatusCode: http.StatusConflict})
+
+	for _, email := range []string{"other@example.com", "new@example.com"} {
+		update := &models.UserUpdateDto{UserEmailId: email, UserFirstName: "Ada", UserLastName: "Admin", UserDisplayName: "ada"}
+		_, err := service.UpdateSelf(context.Background(), admin, update)
+		assert.Equal(t, http.StatusConflict, err.StatusCode, email)
+		assert.Equal(t, constants.USER_ALREADY_EXISTS, err.Message, email)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	service, crudRepo, userRepo, sessionRepo := newTestUserService(t)
+	salt, _ := utils.GenerateSalt()
+	stored := &models.User{ID: 1, UserEmailId: admin.UserEmailId, StoredSalt: salt, EncryptedPassword: utils.HashPassword("old password", salt)}
+	userRepo.On("Get", mock.Anything, admin.UserEmailId).Return(stored, nil)
+
+	err := service.ChangePassword(context.Background(), admin, &models.PasswordUpdateDto{OldPassword: "not the password", NewPassword: "new password"})
+	assert.Equal(t, http.StatusBadRequest, err.StatusCode)
+	assert.Equal(t, constants.OLD_PASSWORD_MISMATCH, err.Message)
+
+	runTransactions(crudRepo, userRepo, sessionRepo)
+	userRepo.On("UpdatePassword", mock.Anything, admin.UserEmailId, mock.Anything, mock.Anything).Return(nil)
+	sessionRepo.On("DeleteByUserID", mock.Anything, admin.ID).Return(nil)
+	err = service.ChangePassword(context.Background(), admin, &models.PasswordUpdateDto{OldPassword: "old password", NewPassword: "new password"})
+	assert.Nil(t, err)
+	userRepo.AssertCalled(t, "UpdatePassword", mock.Anything, admin.UserEmailId,
+		mock.MatchedBy(func(hash string) bool { return hash != stored.EncryptedPassword }), mock.MatchedBy(func(newSalt string) bool { return newSalt != salt }))
+}
+
+func TestDeleteUser(t *testing.T) {
+	service, crudRepo, userRepo, sessionRepo := newTestUserService(t)
+	viewer := &models.User{ID: 5, UserEmailId: "viewer@example.com", UserRole: models.RoleViewer}
+	userRepo.On("GetProfile", mock.Anything, "gone@example.com").
+		Return(nil, &utils.ErrorMessage{StatusCode: http.StatusNotFound, Message: "No user found"})
+	userRepo.On("GetProfile", mock.Anything, viewer.UserEmailId).Return(viewer, nil)
+	runTransactions(crudRepo, userRepo, sessionRepo)
+	sessionRepo.On("DeleteByUserID", mock.Anything, viewer.ID).Return(nil)
+	userRepo.On("Delete", mock.Anything, viewer.UserEmailId).Return(nil)
+
+	err := service.DeleteUser(context.Background(), admin, "gone@example.com")
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+	assert.Nil(t, service.DeleteUser(context.Background(), admin, viewer.UserEmailId))
+}
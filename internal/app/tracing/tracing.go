@@ -0,0 +1,104 @@
+// Package tracing sets up OpenTelemetry and holds the helpers shared by the instrumented layers.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"starter/internal/app/buildinfo"
+	"starter/internal/config"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName names the tracer of the application's own spans.
+const InstrumentationName = "starter"
+
+// Tracer returns the tracer of the application, a no-op one until Setup installed an exporter.
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
+
+// Setup installs the global tracer provider and the W3C propagators. The returned function
+// flushes the spans still buffered and must be called before the process exits.
+func Setup(tracingConfig config.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if tracingConfig.Exporter == "none" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closeOutput, err := newExporter(tracingConfig)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(tracingConfig.ServiceName),
+		semconv.ServiceVersion(buildinfo.Get().Version),
+	))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// Follow the sampling decision of the caller, sample the traces started here by ratio
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(tracingConfig.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return func(ctx context.Context) error {
+		return errors.Join(provider.Shutdown(ctx), closeOutput())
+	}, nil
+}
+
+func newExporter(tracingConfig config.TracingConfig) (sdktrace.SpanExporter, func() error, error) {
+	noClose := func() error { return nil }
+	switch tracingConfig.Exporter {
+	case "otlp":
+		var options []otlptracehttp.Option
+		if tracingConfig.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpointURL(tracingConfig.Endpoint))
+		}
+		exporter, err := otlptracehttp.New(context.Background(), options...)
+		return exporter, noClose, err
+	case "stdout":
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		return exporter, noClose, err
+	case "file":
+		file, err := os.OpenFile(tracingConfig.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("TRACING_FILE: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		return exporter, file.Close, err
+	}
+	return nil, nil, fmt.Errorf("unknown trace exporter %q", tracingConfig.Exporter)
+}
+
+var (
+	// Positional parameters are matched first so that their digits are kept
+	sqlLiteral = regexp.MustCompile(`\$\d+|'(?:[^']|'')*'|\b\d+(?:\.\d+)?\b`)
+	sqlSpaces  = regexp.MustCompile(`\s+`)
+)
+
+// SanitizeSQL replaces the literals of a statement with ? and collapses its whitespace, so that
+// spans never carry values inlined in the SQL, such as the LIMIT of a page. Bound parameters
+// ($1, $2...) are kept, their values are never recorded.
+func SanitizeSQL(query string) string {
+	query = sqlLiteral.ReplaceAllStringFunc(query, func(literal string) string {
+		if strings.HasPrefix(literal, "$") {
+			return literal
+		}
+		return "?"
+	})
+	return strings.TrimSpace(sqlSpaces.ReplaceAllString(query, " "))
+}
@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"starter/internal/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSanitizeSQL(t *testing.T) {
+	cases := map[string]string{
+		`SELECT * FROM users WHERE "userRole" = 'admin' LIMIT 10 OFFSET 20`: `SELECT * FROM users WHERE "userRole" = ? LIMIT ? OFFSET ?`,
+		"UPDATE users\n\tSET name = $1\n\tWHERE id = $2":                    "UPDATE users SET name = $1 WHERE id = $2",
+		`SELECT 'it''s', 1.5, users_v2.id FROM users_v2`:                    `SELECT ?, ?, users_v2.id FROM users_v2`,
+	}
+	for query, expected := range cases {
+		assert.Equal(t, expected, SanitizeSQL(query))
+	}
+}
+
+func TestSetup_FileExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traces.json")
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	shutdown, err := Setup(config.TracingConfig{Exporter: "file", File: file, ServiceName: "starter-test", SampleRatio: 1})
+	require.NoError(t, err)
+
+	_, span := Tracer().Start(context.Background(), "unit")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"Name":"unit"`)
+	assert.Contains(t, string(content), "starter-test")
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, err := Setup(config.TracingConfig{Exporter: "zipkin"})
+	assert.Error(t, err)
+}
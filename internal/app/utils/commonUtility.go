@@ -2,7 +2,6 @@ package utils
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 
 	"github.com/jackc/pgx/v5"
@@ -55,23 +54,3 @@ func ConvertToInt64(val interface{}) (int64, error) {
 }
 
 type RowMapperFunc func(pgx.Row) (interface{}, error)
-
-func GetEnvAsInt(name string, defaultVal int) int {
-	value, exists := os.LookupEnv(name)
-	if !exists {
-		return defaultVal
-	}
-	intValue, err := strconv.Atoi(value)
-	if err != nil {
-		return defaultVal
-	}
-	return intValue
-}
-
-func GetEnvAsString(name string, defaultVal string) string {
-	value, exists := os.LookupEnv(name)
-	if !exists {
-		return defaultVal
-	}
-	return value
-}
@@ -8,6 +8,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// MaxPageSize bounds per_page, larger pages are cut down to it.
+const MaxPageSize = 100
+
 type Pagination struct {
 	Limit      int         `json:"limit,omitempty;query:limit"`
 	Page       int         `json:"page,omitempty;query:page"`
@@ -51,6 +54,9 @@ func PaginateQueryExtractor(context *gin.Context, validSortFields []string) (*Pa
 	if err != nil || pageSize <= 0 {
 		pageSize = 10 // Default to 10 if not specified or invalid
 	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
 	sort := context.Query("sort")
 	direction := context.Query("sortDesc")
 	var sortWithDirection string
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2id parameters used for hashing user passwords.
+const (
+	saltLength    = 16
+	argonTime     = 1
+	argonMemory   = 64 * 1024
+	argonThreads  = 4
+	argonKeyBytes = 32
+)
+
+// GenerateSalt returns a random base64 encoded salt to be stored alongside the user.
+func GenerateSalt() (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return base64.RawStdEncoding.EncodeToString(salt), nil
+}
+
+// HashPassword derives an Argon2id hash from the password and the per-user salt.
+func HashPassword(password string, salt string) string {
+	hash := argon2.IDKey([]byte(password), []byte(salt), argonTime, argonMemory, argonThreads, argonKeyBytes)
+	return base64.RawStdEncoding.EncodeToString(hash)
+}
+
+// VerifyPassword checks the password against the stored hash and salt in constant time.
+func VerifyPassword(password string, salt string, hash string) bool {
+	computed := HashPassword(password, salt)
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(hash)) == 1
+}
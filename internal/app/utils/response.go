@@ -2,6 +2,7 @@ package utils
 
 import (
 	"net/http"
+	"starter/internal/app/logging"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -9,7 +10,7 @@ import (
 
 // RespondJSON is a generic utility to send JSON responses
 func RespondJSON(c *gin.Context, statusCode int, payload interface{}) {
-	logrus.WithFields(logrus.Fields{
+	logging.FromContext(c.Request.Context()).WithFields(logrus.Fields{
 		"status": statusCode,
 		"method": c.Request.Method,
 		"path":   c.Request.URL.Path,
@@ -18,28 +19,20 @@ func RespondJSON(c *gin.Context, statusCode int, payload interface{}) {
 	c.Done()
 }
 
-// ErrorResponse sends error messages
+// ErrorResponse sends error messages, logged with the request ID and user of the request's entry
 func ErrorResponse(c *gin.Context, statusCode int, message string) {
-	requestId, exists := c.Get("RequestID")
-	if exists {
-		logrus.WithFields(logrus.Fields{
-			"status":    statusCode,
-			"method":    c.Request.Method,
-			"path":      c.Request.URL.Path,
-			"RequestId": requestId,
-			"message":   message,
-		}).Error("Error response")
-	} else {
-		logrus.WithFields(logrus.Fields{
-			"status":  statusCode,
-			"method":  c.Request.Method,
-			"path":    c.Request.URL.Path,
-			"message": message,
-		}).Error("Error response")
-	}
+	logging.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"status":  statusCode,
+		"method":  c.Request.Method,
+		"path":    c.Request.URL.Path,
+		"message": message,
+	}).Error("Error response")
 	c.AbortWithStatusJSON(statusCode, gin.H{"error": message})
 }
 
+// StatusClientClosedRequest is the non-standard status used when the client went away before the response was ready.
+const StatusClientClosedRequest = 499
+
 type ErrorMessage struct {
 	StatusCode int
 	Message    string `json:"error"`
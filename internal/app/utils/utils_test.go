@@ -3,8 +3,6 @@ package utils
 import (
 	"net/http"
 	"net/http/httptest"
-	"os"
-	"strconv"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -67,41 +65,6 @@ func TestConvertToInt64(t *testing.T) {
 	}
 }
 
-func TestGetEnvAsInt(t *testing.T) {
-	const envName = "TEST_ENV_INT"
-	const defaultValue = 42
-
-	os.Unsetenv(envName)
-	got := GetEnvAsInt(envName, defaultValue)
-	assert.Equal(t, defaultValue, got, "Expected default value when environment variable is not set")
-
-	expectedValue := 100
-	os.Setenv(envName, strconv.Itoa(expectedValue))
-	got = GetEnvAsInt(envName, defaultValue)
-	assert.Equal(t, expectedValue, got, "Expected value should match the environment variable")
-
-	os.Setenv(envName, "not_an_int")
-	got = GetEnvAsInt(envName, defaultValue)
-	assert.Equal(t, defaultValue, got, "Expected default value when environment variable is not a valid integer")
-
-	os.Unsetenv(envName)
-}
-
-func TestGetEnvAsString(t *testing.T) {
-	const envName = "TEST_ENV_STRING"
-	const defaultValue = "default"
-
-	os.Unsetenv(envName)
-	got := GetEnvAsString(envName, defaultValue)
-	assert.Equal(t, defaultValue, got, "Expected default value when environment variable is not set")
-
-	expectedValue := "test_value"
-	os.Setenv(envName, expectedValue)
-	got = GetEnvAsString(envName, defaultValue)
-	assert.Equal(t, expectedValue, got, "Expected value should match the environment variable")
-	os.Unsetenv(envName)
-}
-
 func TestPagination_GetOffset(t *testing.T) {
 	p := Pagination{Page: 2, Limit: 10}
 	expectedOffset := 10
@@ -154,6 +117,14 @@ func TestPaginateQueryExtractor(t *testing.T) {
 	assert.Equal(t, 200, w.Code, "Expected successful response")
 }
 
+func TestPaginateQueryExtractor_ClampsThePageSize(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/test?per_page=100000000", nil)
+	pagination, errMsg := PaginateQueryExtractor(c, nil)
+	assert.Nil(t, errMsg)
+	assert.Equal(t, MaxPageSize, pagination.Limit)
+}
+
 func TestRespondJSON(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := setupRouter()
@@ -267,3 +238,24 @@ func Test_Int_contains(t *testing.T) {
 		assert.Equal(t, false, IntContains([]int64{1, 2}, 4))
 	})
 }
+
+func TestHashPassword(t *testing.T) {
+	salt, err := GenerateSalt()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, salt)
+
+	hash := HashPassword("password123", salt)
+	assert.NotEqual(t, "password123", hash)
+	assert.Equal(t, hash, HashPassword("password123", salt), "Hash should be deterministic for the same salt")
+
+	otherSalt, _ := GenerateSalt()
+	assert.NotEqual(t, hash, HashPassword("password123", otherSalt), "Different salts should produce different hashes")
+}
+
+func TestVerifyPassword(t *testing.T) {
+	salt, _ := GenerateSalt()
+	hash := HashPassword("password123", salt)
+	assert.True(t, VerifyPassword("password123", salt, hash))
+	assert.False(t, VerifyPassword("wrongpassword", salt, hash))
+	assert.False(t, VerifyPassword("password123", "othersalt", hash))
+}
@@ -0,0 +1,443 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// ProductionEnvironment is the APP_ENV value under which default and weak secrets are refused.
+const ProductionEnvironment = "production"
+
+// Config is the whole application configuration, loaded once at startup by Load.
+// Each field names its environment variable in the env tag, its default in the default tag
+// and its key in a config file in the yaml tag. Fields tagged secret are resolved through the
+// SecretsProvider instead of the environment alone, and are redacted by Redacted.
+type Config struct {
+	App       AppConfig       `yaml:"app"`
+	Secrets   SecretsConfig   `yaml:"secrets"`
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Auth      AuthConfig      `yaml:"auth"`
+	Security  SecurityConfig  `yaml:"security"`
+	RateLimit RateLimitConfig `yaml:"rateLimit"`
+	Health    HealthConfig    `yaml:"health"`
+	Worker    WorkerConfig    `yaml:"worker"`
+	Log       LogConfig       `yaml:"log"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	Setup     SetupConfig     `yaml:"setup"`
+}
+
+type AppConfig struct {
+	Environment string `yaml:"environment" env:"APP_ENV" default:"development"`
+}
+
+// SecretsConfig selects where secrets come from, see NewSecretsProvider.
+type SecretsConfig struct {
+	Providers string `yaml:"providers" env:"SECRETS_PROVIDERS" default:"env,file"`
+	Dir       string `yaml:"dir" env:"SECRETS_DIR" default:"/run/secrets"`
+	File      string `yaml:"file" env:"SECRETS_FILE"`
+}
+
+type ServerConfig struct {
+	Port                string        `yaml:"port" env:"SERVER_PORT" default:"4000"`
+	GinMode             string        `yaml:"ginMode" env:"GIN_MODE" default:"debug"`
+	RequestTimeout      time.Duration `yaml:"requestTimeout" env:"REQUEST_TIMEOUT_SEC" default:"120s"`
+	ShutdownGracePeriod time.Duration `yaml:"shutdownGracePeriod" env:"SHUTDOWN_GRACE_PERIOD" default:"30s"`
+	ShutdownDrainDelay  time.Duration `yaml:"shutdownDrainDelay" env:"SHUTDOWN_DRAIN_DELAY" default:"0s"`
+	RunWorker           bool          `yaml:"runWorker" env:"SERVER_RUN_WORKER" default:"false"`
+	TrustedProxies      string        `yaml:"trustedProxies" env:"TRUSTED_PROXIES"`
+}
+
+// TrustedProxyList returns the comma separated IPs and CIDRs of TrustedProxies, nil when there are none.
+func (s ServerConfig) TrustedProxyList() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(s.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+type DatabaseConfig struct {
+	User               string        `yaml:"user" env:"DB_USER" default:"postgres"`
+	Password           string        `yaml:"password" env:"DB_PASSWORD" default:"postgres" secret:"true"`
+	Host               string        `yaml:"host" env:"DB_HOST" default:"127.0.0.1"`
+	Port               int           `yaml:"port" env:"DB_PORT" default:"5432"`
+	Name               string        `yaml:"name" env:"DB_NAME" default:"wtbbe_dev"`
+	SSLMode            string        `yaml:"sslMode" env:"DB_SSL_MODE" default:"disable"`
+	MaxPoolConnections int           `yaml:"maxPoolConnections" env:"DB_MAX_POOL_CONNECTIONS" default:"10"`
+	MinPoolConnections int           `yaml:"minPoolConnections" env:"DB_MIN_POOL_CONNECTIONS" default:"1"`
+	MaxConnLifetime    time.Duration `yaml:"maxConnLifetime" env:"DB_MAX_CONN_LIFETIME" default:"30m"`
+	MaxConnIdleTime    time.Duration `yaml:"maxConnIdleTime" env:"DB_MAX_CONN_IDLE_TIME" default:"10m"`
+	QueryTimeout       time.Duration `yaml:"queryTimeout" env:"DB_QUERY_TIMEOUT" default:"30s"`
+	TxIsolationLevel   string        `yaml:"txIsolationLevel" env:"DB_TX_ISOLATION_LEVEL" default:"read committed"`
+}
+
+// AuthConfig signs tokens with JWTSecret, or with the active key of JWTSecrets ("id:secret,...")
+// when rotating. Tokens carry the key ID so that older keys keep validating until they are removed.
+type AuthConfig struct {
+	JWTSecret      string        `yaml:"jwtSecret" env:"JWT_SECRET" default:"change-me-in-production" secret:"true"`
+	JWTSecrets     string        `yaml:"jwtSecrets" env:"JWT_SECRETS" secret:"true"`
+	JWTActiveKeyID string        `yaml:"jwtActiveKeyId" env:"JWT_ACTIVE_KEY_ID"`
+	TokenTTL       time.Duration `yaml:"tokenTTL" env:"JWT_TOKEN_TTL" default:"1h"`
+}
+
+// SecurityConfig holds the field encryption keys, AESKey alone or AESKeys ("id:key,...") when rotating.
+// BlindIndexKey hashes searchable fields and must outlive AES key rotations, so it is a separate key,
+// derived from AESKey when unset.
+type SecurityConfig struct {
+	AESKey         string `yaml:"aesKey" env:"AES_KEY" default:"1234567812345678" secret:"true"`
+	AESKeys        string `yaml:"aesKeys" env:"AES_KEYS" secret:"true"`
+	AESActiveKeyID string `yaml:"aesActiveKeyId" env:"AES_ACTIVE_KEY_ID"`
+	BlindIndexKey  string `yaml:"blindIndexKey" env:"BLIND_INDEX_KEY" secret:"true"`
+}
+
+// JWTKeyring returns the signing keys, valid once Validate has passed.
+func (a AuthConfig) JWTKeyring() Keyring {
+	keyring, _ := ParseKeyring(a.JWTSecret, a.JWTSecrets, a.JWTActiveKeyID)
+	return keyring
+}
+
+// AESKeyring returns the encryption keys, valid once Validate has passed.
+func (s SecurityConfig) AESKeyring() Keyring {
+	keyring, _ := ParseKeyring(s.AESKey, s.AESKeys, s.AESActiveKeyID)
+	return keyring
+}
+
+// BlindIndexKeyBytes returns BlindIndexKey, or a key derived from AESKey when it is unset.
+func (s SecurityConfig) BlindIndexKeyBytes() []byte {
+	if s.BlindIndexKey != "" {
+		key, _ := decodeKey(s.BlindIndexKey)
+		return key
+	}
+	if s.AESKeys != "" || s.AESKey == "" {
+		return nil
+	}
+	key, _ := decodeKey(s.AESKey)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("blind-index"))
+	return mac.Sum(nil)
+}
+
+// RateLimitConfig sets the budget of each client per route group, as "<requests>/<period>" such as
+// "20/m" or "5/10s", or "off". Groups left empty get PerSecond requests per second. Store is memory
+// to count per replica or postgres to share the counts between replicas. UserIP limits each IP on
+// /user before authentication, so that rejected tokens count too.
+type RateLimitConfig struct {
+	Store     string        `yaml:"store" env:"RATE_LIMIT_STORE" default:"memory"`
+	PerSecond int           `yaml:"perSecond" env:"RATE_LIMIT_PER_SEC" default:"100"`
+	Auth      string        `yaml:"auth" env:"RATE_LIMIT_AUTH" default:"20/m"`
+	User      string        `yaml:"user" env:"RATE_LIMIT_USER"`
+	UserIP    string        `yaml:"userIP" env:"RATE_LIMIT_USER_IP"`
+	Internal  string        `yaml:"internal" env:"RATE_LIMIT_INTERNAL"`
+	Health    string        `yaml:"health" env:"RATE_LIMIT_HEALTH"`
+	IdleTTL   time.Duration `yaml:"idleTTL" env:"RATE_LIMIT_IDLE_TTL" default:"10m"`
+}
+
+// RateLimitRule lets Requests requests through per Period, in bursts of up to Requests.
+// The zero rule means no limit.
+type RateLimitRule struct {
+	Requests int
+	Period   time.Duration
+}
+
+func (r RateLimitRule) Disabled() bool {
+	return r.Requests == 0
+}
+
+// Rule parses the spec of a route group, see RateLimitConfig.
+func (r RateLimitConfig) Rule(spec string) (RateLimitRule, error) {
+	switch strings.TrimSpace(spec) {
+	case "":
+		return RateLimitRule{Requests: r.PerSecond, Period: time.Second}, nil
+	case "off":
+		return RateLimitRule{}, nil
+	}
+	rawRequests, rawPeriod, found := strings.Cut(spec, "/")
+	requests, err := strconv.Atoi(strings.TrimSpace(rawRequests))
+	if !found || err != nil || requests <= 0 {
+		return RateLimitRule{}, fmt.Errorf("%q is not like 20/m", spec)
+	}
+	period, ok := map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour}[strings.TrimSpace(rawPeriod)]
+	if !ok {
+		period, err = time.ParseDuration(strings.TrimSpace(rawPeriod))
+		if err != nil || period <= 0 {
+			return RateLimitRule{}, fmt.Errorf("%q has an invalid period, use s, m, h or a duration", spec)
+		}
+	}
+	return RateLimitRule{Requests: requests, Period: period}, nil
+}
+
+type HealthConfig struct {
+	CacheTTL              time.Duration `yaml:"cacheTTL" env:"HEALTH_CACHE_TTL" default:"5s"`
+	CheckTimeout          time.Duration `yaml:"checkTimeout" env:"HEALTH_CHECK_TIMEOUT" default:"2s"`
+	PoolSaturationPercent int           `yaml:"poolSaturationPercent" env:"HEALTH_POOL_SATURATION_PERCENT" default:"90"`
+	DiskMinFreeMB         int           `yaml:"diskMinFreeMB" env:"HEALTH_DISK_MIN_FREE_MB" default:"100"`
+	PartnerURL            string        `yaml:"partnerURL" env:"HEALTH_PARTNER_URL"`
+}
+
+type WorkerConfig struct {
+	Interval time.Duration `yaml:"interval" env:"WORKER_INTERVAL" default:"15m"`
+}
+
+// LogConfig selects the log sinks: stdout (JSON), text (human readable on stdout, for development)
+// and file (JSON in File, rotated once it reaches MaxSizeMB or every RotateEvery, 0 disabling either).
+// Rotated files are gzipped when Compress is set and the MaxBackups most recent ones are kept, all when 0.
+// The values of the RedactParams query parameters are masked in every entry, along with emails and credentials.
+type LogConfig struct {
+	Level        string        `yaml:"level" env:"APPLICATION_LOG_LEVEL" default:"info"`
+	Sinks        string        `yaml:"sinks" env:"LOG_SINKS" default:"stdout,file"`
+	File         string        `yaml:"file" env:"LOG_FILE" default:"application.log"`
+	FileMode     string        `yaml:"fileMode" env:"LOG_FILE_MODE" default:"0640"`
+	MaxSizeMB    int           `yaml:"maxSizeMB" env:"LOG_MAX_SIZE_MB" default:"100"`
+	RotateEvery  time.Duration `yaml:"rotateEvery" env:"LOG_ROTATE_EVERY" default:"24h"`
+	MaxBackups   int           `yaml:"maxBackups" env:"LOG_MAX_BACKUPS" default:"7"`
+	Compress     bool          `yaml:"compress" env:"LOG_COMPRESS" default:"true"`
+	RedactParams string        `yaml:"redactParams" env:"LOG_REDACT_PARAMS" default:"token,access_token,refresh_token,key,api_key,apikey,password,secret,signature,sig,code"`
+}
+
+// SinkList returns the comma separated sinks of Sinks.
+func (l LogConfig) SinkList() []string {
+	var sinks []string
+	for _, sink := range strings.Split(l.Sinks, ",") {
+		if sink = strings.TrimSpace(sink); sink != "" {
+			sinks = append(sinks, sink)
+		}
+	}
+	return sinks
+}
+
+// RedactParamList returns the comma separated query parameter names of RedactParams.
+func (l LogConfig) RedactParamList() []string {
+	var params []string
+	for _, param := range strings.Split(l.RedactParams, ",") {
+		if param = strings.TrimSpace(param); param != "" {
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// ConsoleSinks returns the sinks other than file, stdout when there are none.
+func (l LogConfig) ConsoleSinks() []string {
+	var sinks []string
+	for _, sink := range l.SinkList() {
+		if sink != "file" {
+			sinks = append(sinks, sink)
+		}
+	}
+	if len(sinks) == 0 {
+		sinks = append(sinks, "stdout")
+	}
+	return sinks
+}
+
+// HasSink tells whether name is one of the configured sinks.
+func (l LogConfig) HasSink(name string) bool {
+	for _, sink := range l.SinkList() {
+		if sink == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Mode parses FileMode, an octal permission such as 0640.
+func (l LogConfig) Mode() (os.FileMode, error) {
+	mode, err := strconv.ParseUint(l.FileMode, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("%q is not an octal file permission", l.FileMode)
+	}
+	return os.FileMode(mode), nil
+}
+
+// TracingConfig selects where OpenTelemetry spans go: none, otlp (OTLP over HTTP to Endpoint, or to
+// the standard OTEL_EXPORTER_OTLP_* variables when empty), stdout, or file to write them as JSON lines.
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" default:"none"`
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	File        string  `yaml:"file" env:"TRACING_FILE" default:"traces.json"`
+	ServiceName string  `yaml:"serviceName" env:"OTEL_SERVICE_NAME" default:"starter"`
+	SampleRatio float64 `yaml:"sampleRatio" env:"TRACING_SAMPLE_RATIO" default:"1"`
+}
+
+// SetupConfig holds the inputs of the one-off seed and create-admin commands.
+type SetupConfig struct {
+	SeedFile      string `yaml:"seedFile" env:"SEED_FILE" default:"seed.sql"`
+	AdminPassword string `yaml:"adminPassword" env:"ADMIN_PASSWORD" secret:"true"`
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	port, err := strconv.Atoi(c.Server.Port)
+	check(err == nil && port > 0 && port < 65536, "SERVER_PORT: %q is not a valid port", c.Server.Port)
+	check(c.Server.GinMode == gin.DebugMode || c.Server.GinMode == gin.ReleaseMode || c.Server.GinMode == gin.TestMode,
+		"GIN_MODE: %q must be one of debug, release or test", c.Server.GinMode)
+	check(c.Server.RequestTimeout > 0, "REQUEST_TIMEOUT_SEC: must be positive")
+	check(c.Server.ShutdownGracePeriod > 0, "SHUTDOWN_GRACE_PERIOD: must be positive")
+	check(c.Server.ShutdownDrainDelay >= 0, "SHUTDOWN_DRAIN_DELAY: must not be negative")
+	for _, proxy := range c.Server.TrustedProxyList() {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(cidrErr == nil || net.ParseIP(proxy) != nil, "TRUSTED_PROXIES: %q is neither an IP nor a CIDR", proxy)
+	}
+
+	check(c.Database.Host != "", "DB_HOST: must not be empty")
+	check(c.Database.Name != "", "DB_NAME: must not be empty")
+	check(c.Database.Port > 0 && c.Database.Port < 65536, "DB_PORT: %d is not a valid port", c.Database.Port)
+	check(c.Database.MaxPoolConnections > 0, "DB_MAX_POOL_CONNECTIONS: must be positive")
+	check(c.Database.MinPoolConnections >= 0 && c.Database.MinPoolConnections <= c.Database.MaxPoolConnections,
+		"DB_MIN_POOL_CONNECTIONS: must be between 0 and DB_MAX_POOL_CONNECTIONS")
+	check(c.Database.QueryTimeout >= 0, "DB_QUERY_TIMEOUT: must not be negative")
+	switch pgx.TxIsoLevel(c.Database.TxIsolationLevel) {
+	case pgx.Serializable, pgx.RepeatableRead, pgx.ReadCommitted, pgx.ReadUncommitted:
+	default:
+		errs = append(errs, fmt.Errorf("DB_TX_ISOLATION_LEVEL: %q is not a Postgres isolation level", c.Database.TxIsolationLevel))
+	}
+
+	check(c.Auth.TokenTTL > 0, "JWT_TOKEN_TTL: must be positive")
+	jwtKeys, err := ParseKeyring(c.Auth.JWTSecret, c.Auth.JWTSecrets, c.Auth.JWTActiveKeyID)
+	check(err == nil, "%s: %v", keySetting("JWT_SECRET", c.Auth.JWTSecrets, ""), err)
+	aesKeys, err := ParseKeyring(c.Security.AESKey, c.Security.AESKeys, c.Security.AESActiveKeyID)
+	check(err == nil, "%s: %v", keySetting("AES_KEY", c.Security.AESKeys, ""), err)
+	for id, key := range aesKeys.Keys {
+		check(len(key) == 16 || len(key) == 24 || len(key) == 32, "%s: must be 16, 24 or 32 bytes long, got %d",
+			keySetting("AES_KEY", c.Security.AESKeys, id), len(key))
+	}
+	if c.Security.BlindIndexKey != "" {
+		_, err = decodeKey(c.Security.BlindIndexKey)
+		check(err == nil, "BLIND_INDEX_KEY: %v", err)
+	} else {
+		check(c.Security.AESKeys == "" && !c.IsProduction(),
+			"BLIND_INDEX_KEY: must be set in production and with AES_KEYS, the email index must not depend on a rotating key")
+	}
+	check(c.RateLimit.Store == "memory" || c.RateLimit.Store == "postgres", "RATE_LIMIT_STORE: %q must be memory or postgres", c.RateLimit.Store)
+	check(c.RateLimit.PerSecond > 0, "RATE_LIMIT_PER_SEC: must be positive")
+	for name, spec := range map[string]string{"RATE_LIMIT_AUTH": c.RateLimit.Auth, "RATE_LIMIT_USER": c.RateLimit.User,
+		"RATE_LIMIT_USER_IP": c.RateLimit.UserIP, "RATE_LIMIT_INTERNAL": c.RateLimit.Internal, "RATE_LIMIT_HEALTH": c.RateLimit.Health} {
+		_, err := c.RateLimit.Rule(spec)
+		check(err == nil, "%s: %v", name, err)
+	}
+	check(c.RateLimit.IdleTTL > 0, "RATE_LIMIT_IDLE_TTL: must be positive")
+
+	check(c.Health.CacheTTL >= 0, "HEALTH_CACHE_TTL: must not be negative")
+	check(c.Health.CheckTimeout > 0, "HEALTH_CHECK_TIMEOUT: must be positive")
+	check(c.Health.PoolSaturationPercent > 0 && c.Health.PoolSaturationPercent <= 100,
+		"HEALTH_POOL_SATURATION_PERCENT: must be between 1 and 100")
+	check(c.Health.DiskMinFreeMB >= 0, "HEALTH_DISK_MIN_FREE_MB: must not be negative")
+	if c.Health.PartnerURL != "" {
+		_, err := url.ParseRequestURI(c.Health.PartnerURL)
+		check(err == nil, "HEALTH_PARTNER_URL: %q is not a valid URL", c.Health.PartnerURL)
+	}
+	check(c.Worker.Interval > 0, "WORKER_INTERVAL: must be positive")
+
+	switch c.Tracing.Exporter {
+	case "none", "otlp", "stdout", "file":
+	default:
+		errs = append(errs, fmt.Errorf("TRACING_EXPORTER: %q must be none, otlp, stdout or file", c.Tracing.Exporter))
+	}
+	if c.Tracing.Endpoint != "" {
+		_, err := url.ParseRequestURI(c.Tracing.Endpoint)
+		check(err == nil, "TRACING_OTLP_ENDPOINT: %q is not a valid URL", c.Tracing.Endpoint)
+	}
+	check(c.Tracing.Exporter != "file" || c.Tracing.File != "", "TRACING_FILE: must not be empty with the file exporter")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO: must be between 0 and 1")
+
+	_, err = logrus.ParseLevel(c.Log.Level)
+	check(err == nil, "APPLICATION_LOG_LEVEL: %q is not a log level", c.Log.Level)
+	check(len(c.Log.SinkList()) > 0, "LOG_SINKS: must name at least one sink")
+	for _, sink := range c.Log.SinkList() {
+		check(sink == "stdout" || sink == "text" || sink == "file", "LOG_SINKS: %q must be stdout, text or file", sink)
+	}
+	check(!c.Log.HasSink("stdout") || !c.Log.HasSink("text"), "LOG_SINKS: stdout and text both write to stdout, pick one")
+	if c.Log.HasSink("file") {
+		check(c.Log.File != "", "LOG_FILE: must not be empty with the file sink")
+		_, modeErr := c.Log.Mode()
+		check(modeErr == nil, "LOG_FILE_MODE: %v", modeErr)
+		check(c.Log.MaxSizeMB >= 0, "LOG_MAX_SIZE_MB: must not be negative")
+		check(c.Log.RotateEvery == 0 || c.Log.RotateEvery >= time.Minute, "LOG_ROTATE_EVERY: must be 0 or at least 1m")
+		check(c.Log.MaxBackups >= 0, "LOG_MAX_BACKUPS: must not be negative")
+	}
+
+	if c.IsProduction() {
+		errs = append(errs, c.validateProductionSecrets(jwtKeys, aesKeys)...)
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Config) IsProduction() bool {
+	return c.App.Environment == ProductionEnvironment
+}
+
+// validateProductionSecrets refuses the secrets shipped as defaults and keys that are easy to guess.
+func (c *Config) validateProductionSecrets(jwtKeys, aesKeys Keyring) []error {
+	var errs []error
+	if c.Database.Password == "" || c.Database.Password == Defaults().Database.Password {
+		errs = append(errs, errors.New("DB_PASSWORD: the default or an empty password is not allowed in production"))
+	}
+	for id, key := range jwtKeys.Keys {
+		if reason := weakKey(key, 32); reason != "" {
+			errs = append(errs, fmt.Errorf("%s: too weak for production, %s", keySetting("JWT_SECRET", c.Auth.JWTSecrets, id), reason))
+		}
+	}
+	for id, key := range aesKeys.Keys {
+		if reason := weakKey(key, 16); reason != "" {
+			errs = append(errs, fmt.Errorf("%s: too weak for production, %s", keySetting("AES_KEY", c.Security.AESKeys, id), reason))
+		}
+	}
+	if reason := weakKey(c.Security.BlindIndexKeyBytes(), 32); c.Security.BlindIndexKey != "" && reason != "" {
+		errs = append(errs, fmt.Errorf("BLIND_INDEX_KEY: too weak for production, %s", reason))
+	}
+	if c.Server.GinMode != gin.ReleaseMode {
+		errs = append(errs, errors.New("GIN_MODE: must be release in production"))
+	}
+	return errs
+}
+
+// keySetting names the setting a key came from in error messages, e.g. AES_KEY or AES_KEYS[v2].
+func keySetting(single, list, id string) string {
+	switch {
+	case list == "":
+		return single
+	case id == "":
+		return single + "S"
+	}
+	return fmt.Sprintf("%sS[%s]", single, id)
+}
+
+// ConnectionURL builds the pgx connection string, pool settings included.
+func (d DatabaseConfig) ConnectionURL() string {
+	query := url.Values{}
+	query.Set("sslmode", d.SSLMode)
+	query.Set("pool_max_conns", strconv.Itoa(d.MaxPoolConnections))
+	query.Set("pool_min_conns", strconv.Itoa(d.MinPoolConnections))
+	query.Set("pool_max_conn_lifetime", d.MaxConnLifetime.String())
+	query.Set("pool_max_conn_idle_time", d.MaxConnIdleTime.String())
+	connectionURL := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(d.User, d.Password),
+		Host:     fmt.Sprintf("%s:%d", d.Host, d.Port),
+		Path:     "/" + d.Name,
+		RawQuery: query.Encode(),
+	}
+	return connectionURL.String()
+}
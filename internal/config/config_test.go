@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaults_AreValid(t *testing.T) {
+	assert.NoError(t, Defaults().Validate())
+}
+
+func TestLoad_EnvOverridesFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	content := "server:\n  port: 8080\n  requestTimeout: 10s\ndatabase:\n  host: db.internal\n  maxPoolConnections: 20\n"
+	assert.NoError(t, os.WriteFile(file, []byte(content), 0600))
+	t.Setenv("CONFIG_FILE", file)
+	t.Setenv("DB_HOST", "db.env")
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "8080", cfg.Server.Port)
+	assert.Equal(t, 10*time.Second, cfg.Server.RequestTimeout)
+	assert.Equal(t, 20, cfg.Database.MaxPoolConnections)
+	assert.Equal(t, "db.env", cfg.Database.Host)
+	assert.Equal(t, "postgres", cfg.Database.User)
+}
+
+func TestLoad_JSONFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(file, []byte(`{"rateLimit": {"perSecond": 7}, "server": {"runWorker": true}}`), 0600))
+	t.Setenv("CONFIG_FILE", file)
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, 7, cfg.RateLimit.PerSecond)
+	assert.True(t, cfg.Server.RunWorker)
+}
+
+func TestLoad_AggregatesErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte("server:\n  colour: blue\n"), 0600))
+	t.Setenv("CONFIG_FILE", file)
+	t.Setenv("DB_PORT", "not_a_port")
+	t.Setenv("JWT_TOKEN_TTL", "forever")
+	t.Setenv("AES_KEY", "short")
+
+	_, err := Load()
+	assert.Error(t, err)
+	for _, expected := range []string{"server.colour", "DB_PORT", "JWT_TOKEN_TTL", "AES_KEY"} {
+		assert.True(t, strings.Contains(err.Error(), expected), "missing %s in %v", expected, err)
+	}
+}
+
+func TestRedacted_HidesSecrets(t *testing.T) {
+	cfg := Defaults()
+	cfg.Setup.AdminPassword = ""
+	redacted := cfg.Redacted()
+	assert.Equal(t, redactedValue, redacted["database"]["password"])
+	assert.Equal(t, redactedValue, redacted["auth"]["jwtSecret"])
+	assert.Equal(t, redactedValue, redacted["security"]["aesKey"])
+	assert.Equal(t, "", redacted["setup"]["adminPassword"])
+	assert.Equal(t, "postgres", redacted["database"]["user"])
+	assert.Equal(t, "30s", redacted["database"]["queryTimeout"])
+}
+
+func TestConnectionURL_EscapesCredentials(t *testing.T) {
+	cfg := Defaults().Database
+	cfg.Password = "p@ss/word"
+	assert.Contains(t, cfg.ConnectionURL(), "postgres:p%40ss%2Fword@127.0.0.1:5432/wtbbe_dev?")
+}
+
+func TestRateLimitConfig_Rule(t *testing.T) {
+	rateLimit := Defaults().RateLimit
+	for spec, expected := range map[string]RateLimitRule{
+		"":      {Requests: 100, Period: time.Second},
+		"off":   {},
+		"20/m":  {Requests: 20, Period: time.Minute},
+		"5/10s": {Requests: 5, Period: 10 * time.Second},
+		" 3/h ": {Requests: 3, Period: time.Hour},
+	} {
+		rule, err := rateLimit.Rule(spec)
+		assert.NoError(t, err, spec)
+		assert.Equal(t, expected, rule, spec)
+	}
+	for _, spec := range []string{"20", "0/s", "x/m", "5/fortnight", "5/-1s"} {
+		_, err := rateLimit.Rule(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestValidate_LogSinks(t *testing.T) {
+	cfg := Defaults()
+	cfg.Log.Sinks = "stdout,text,file,syslog"
+	cfg.Log.FileMode = "0999"
+	err := cfg.Validate()
+	assert.Error(t, err)
+	for _, expected := range []string{`"syslog" must be stdout, text or file`, "pick one", "LOG_FILE_MODE"} {
+		assert.Contains(t, err.Error(), expected)
+	}
+
+	cfg = Defaults()
+	cfg.Log.Sinks = "text"
+	cfg.Log.FileMode = "invalid"
+	assert.NoError(t, cfg.Validate(), "file settings are only checked with the file sink")
+	mode, err := Defaults().Log.Mode()
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), mode)
+}
+
+func TestLogConfig_ConsoleSinks(t *testing.T) {
+	assert.Equal(t, []string{"stdout"}, LogConfig{Sinks: "file"}.ConsoleSinks())
+	assert.Equal(t, []string{"text"}, LogConfig{Sinks: "text, file"}.ConsoleSinks())
+}
+
+func TestLogConfig_RedactParamList(t *testing.T) {
+	logConfig := LogConfig{RedactParams: " code, ,signature "}
+	assert.Equal(t, []string{"code", "signature"}, logConfig.RedactParamList())
+	assert.Contains(t, Defaults().Log.RedactParamList(), "access_token")
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LegacyKeyID names the key given through a single-key setting such as AES_KEY or JWT_SECRET,
+// and is assumed for data or tokens that carry no key ID.
+const LegacyKeyID = "v1"
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Keyring holds versioned keys to allow rotation: new data is protected with the active key
+// while older keys stay available to read what they protected.
+type Keyring struct {
+	ActiveID string
+	Keys     map[string][]byte
+}
+
+// Active returns the key new data must be protected with.
+func (k Keyring) Active() (string, []byte) {
+	return k.ActiveID, k.Keys[k.ActiveID]
+}
+
+func (k Keyring) Key(id string) ([]byte, bool) {
+	key, ok := k.Keys[id]
+	return key, ok
+}
+
+// ParseKeyring reads keys given as "id:key,id:key", falling back to single under LegacyKeyID when
+// list is empty. Keys prefixed with "base64:" are decoded. activeID defaults to the first key listed.
+func ParseKeyring(single, list, activeID string) (Keyring, error) {
+	keyring := Keyring{Keys: map[string][]byte{}}
+	if strings.TrimSpace(list) == "" {
+		if single == "" {
+			return keyring, fmt.Errorf("no key configured")
+		}
+		key, err := decodeKey(single)
+		if err != nil {
+			return keyring, err
+		}
+		keyring.Keys[LegacyKeyID] = key
+		keyring.ActiveID = LegacyKeyID
+		if activeID != "" && activeID != LegacyKeyID {
+			return keyring, fmt.Errorf("active key %q is not configured", activeID)
+		}
+		return keyring, nil
+	}
+
+	for _, entry := range strings.Split(list, ",") {
+		id, raw, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found || !keyIDPattern.MatchString(id) || raw == "" {
+			return keyring, fmt.Errorf("keys must be listed as id:key, with ids made of letters, digits, - and _")
+		}
+		if _, exists := keyring.Keys[id]; exists {
+			return keyring, fmt.Errorf("key %q is listed twice", id)
+		}
+		key, err := decodeKey(raw)
+		if err != nil {
+			return keyring, fmt.Errorf("key %q: %w", id, err)
+		}
+		keyring.Keys[id] = key
+		if keyring.ActiveID == "" {
+			keyring.ActiveID = id
+		}
+	}
+	if activeID != "" {
+		if _, ok := keyring.Keys[activeID]; !ok {
+			return keyring, fmt.Errorf("active key %q is not configured", activeID)
+		}
+		keyring.ActiveID = activeID
+	}
+	return keyring, nil
+}
+
+func decodeKey(raw string) ([]byte, error) {
+	encoded, isBase64 := strings.CutPrefix(raw, "base64:")
+	if !isBase64 {
+		return []byte(raw), nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 key")
+	}
+	return key, nil
+}
+
+// weakKey tells why a key must not be used in production, or returns "" when it is acceptable.
+func weakKey(key []byte, minLength int) string {
+	if len(key) < minLength {
+		return fmt.Sprintf("shorter than %d bytes", minLength)
+	}
+	distinct := map[byte]bool{}
+	for _, b := range key {
+		distinct[b] = true
+	}
+	if len(distinct) < 10 {
+		return "too few distinct characters"
+	}
+	lowered := strings.ToLower(string(key))
+	for _, word := range []string{"password", "secret", "changeme", "change-me", "postgres", "123456", "admin"} {
+		if strings.Contains(lowered, word) {
+			return fmt.Sprintf("contains %q", word)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const redactedValue = "[REDACTED]"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Load builds the configuration from, in increasing priority, the default tags, the YAML or
+// JSON file named by CONFIG_FILE, the .env file and the environment. Secret settings are then
+// taken from the SecretsProvider when it knows them. Parse and validation errors are all
+// returned together.
+func Load() (*Config, error) {
+	// .env never overrides variables that are already set
+	_ = godotenv.Load()
+
+	cfg := &Config{}
+	var errs []error
+	walk(cfg, func(field reflect.StructField, value reflect.Value, _ string) {
+		if def, ok := field.Tag.Lookup("default"); ok {
+			if err := setValue(value, def); err != nil {
+				errs = append(errs, fmt.Errorf("default of %s: %w", field.Tag.Get("env"), err))
+			}
+		}
+	})
+	if file := os.Getenv("CONFIG_FILE"); file != "" {
+		if err := loadFile(cfg, file); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	walk(cfg, func(field reflect.StructField, value reflect.Value, _ string) {
+		name := field.Tag.Get("env")
+		if raw, ok := os.LookupEnv(name); ok && name != "" {
+			if err := setValue(value, raw); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+	})
+	if err := resolveSecrets(cfg); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, cfg.Validate())
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func resolveSecrets(cfg *Config) error {
+	provider, err := NewSecretsProvider(cfg.Secrets)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	walk(cfg, func(field reflect.StructField, value reflect.Value, _ string) {
+		if field.Tag.Get("secret") != "true" {
+			return
+		}
+		name := field.Tag.Get("env")
+		secret, found, err := provider.Lookup(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			return
+		}
+		if found {
+			value.SetString(secret)
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// Defaults returns the configuration made of the default tags only.
+func Defaults() *Config {
+	cfg := &Config{}
+	walk(cfg, func(field reflect.StructField, value reflect.Value, _ string) {
+		if def, ok := field.Tag.Lookup("default"); ok {
+			_ = setValue(value, def)
+		}
+	})
+	return cfg
+}
+
+// Redacted returns the configuration keyed like a config file, with secrets hidden.
+func (c *Config) Redacted() map[string]map[string]interface{} {
+	redacted := map[string]map[string]interface{}{}
+	walk(c, func(field reflect.StructField, value reflect.Value, section string) {
+		if redacted[section] == nil {
+			redacted[section] = map[string]interface{}{}
+		}
+		var shown interface{}
+		switch {
+		case field.Tag.Get("secret") == "true":
+			if !value.IsZero() {
+				shown = redactedValue
+			} else {
+				shown = ""
+			}
+		case value.Type() == durationType:
+			shown = value.Interface().(time.Duration).String()
+		default:
+			shown = value.Interface()
+		}
+		redacted[section][field.Tag.Get("yaml")] = shown
+	})
+	return redacted
+}
+
+// loadFile applies a YAML or JSON file; JSON is read as YAML, of which it is a subset.
+// Values are strings or scalars parsed like their environment counterparts.
+func loadFile(cfg *Config, file string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("CONFIG_FILE: %w", err)
+	}
+	var sections map[string]map[string]interface{}
+	if err := yaml.Unmarshal(content, &sections); err != nil {
+		return fmt.Errorf("CONFIG_FILE: %s is neither valid YAML nor JSON: %w", file, err)
+	}
+	known := map[string]map[string]bool{}
+	var errs []error
+	walk(cfg, func(field reflect.StructField, value reflect.Value, section string) {
+		key := field.Tag.Get("yaml")
+		if known[section] == nil {
+			known[section] = map[string]bool{}
+		}
+		known[section][key] = true
+		raw, ok := sections[section][key]
+		if !ok || raw == nil {
+			return
+		}
+		if err := setValue(value, fmt.Sprint(raw)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s.%s: %w", file, section, key, err))
+		}
+	})
+	for section, keys := range sections {
+		for key := range keys {
+			if !known[section][key] {
+				errs = append(errs, fmt.Errorf("%s: unknown setting %s.%s", file, section, key))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// walk calls fn for every setting of cfg with the yaml name of its section.
+func walk(cfg *Config, fn func(field reflect.StructField, value reflect.Value, section string)) {
+	root := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Type().Field(i).Tag.Get("yaml")
+		sectionValue := root.Field(i)
+		for j := 0; j < sectionValue.NumField(); j++ {
+			fn(sectionValue.Type().Field(j), sectionValue.Field(j), section)
+		}
+	}
+}
+
+func setValue(value reflect.Value, raw string) error {
+	switch {
+	case value.Type() == durationType:
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration", raw)
+		}
+		value.SetInt(int64(duration))
+	case value.Kind() == reflect.String:
+		value.SetString(raw)
+	case value.Kind() == reflect.Int:
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", raw)
+		}
+		value.SetInt(int64(number))
+	case value.Kind() == reflect.Float64:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		value.SetFloat(number)
+	case value.Kind() == reflect.Bool:
+		flag, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		value.SetBool(flag)
+	default:
+		return fmt.Errorf("unsupported setting type %s", value.Type())
+	}
+	return nil
+}
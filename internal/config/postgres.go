@@ -2,10 +2,9 @@ package config
 
 import (
 	"context"
-	"fmt"
-	"starter/internal/app/utils"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sirupsen/logrus"
 )
@@ -14,6 +13,8 @@ import (
 type DBPool interface {
 	Ping(ctx context.Context) error
 	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 	Close()
@@ -21,24 +22,9 @@ type DBPool interface {
 
 var _ DBPool = (*pgxpool.Pool)(nil)
 
-func getConnectionURL() string {
-	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s&pool_max_conns=%s&pool_min_conns=%s&pool_max_conn_lifetime=%s&pool_max_conn_idle_time=%s",
-		utils.GetEnvAsString("DB_USER", "postgres"),
-		utils.GetEnvAsString("DB_PASSWORD", "postgres"),
-		utils.GetEnvAsString("DB_HOST", "127.0.0.1"),
-		utils.GetEnvAsString("DB_PORT", "5432"),
-		utils.GetEnvAsString("DB_NAME", "wtbbe_dev"),
-		utils.GetEnvAsString("DB_SSL_MODE", "disable"),
-		utils.GetEnvAsString("DB_MAX_POOL_CONNECTIONS", "10"),
-		utils.GetEnvAsString("DB_MIN_POOL_CONNECTIONS", "1"),
-		utils.GetEnvAsString("DB_MAX_CONN_LIFETIME", "30m"),
-		utils.GetEnvAsString("DB_MAX_CONN_IDLE_TIME", "10m"),
-	)
-}
-
-func ConnectDB() DBPool {
+func ConnectDB(cfg DatabaseConfig) DBPool {
 	// Construct the connection string
-	connectionURL := getConnectionURL()
+	connectionURL := cfg.ConnectionURL()
 
 	// Connect to the database
 	dbConnection, err := pgxpool.New(context.Background(), connectionURL)
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SecretsProvider resolves secret settings by their environment variable name, e.g. DB_PASSWORD.
+type SecretsProvider interface {
+	Name() string
+	Lookup(name string) (value string, found bool, err error)
+}
+
+// NewSecretsProvider chains the providers listed in SECRETS_PROVIDERS, the first one knowing
+// a secret wins. The encrypted provider needs SECRETS_FILE and the SECRETS_MASTER_KEY variable.
+func NewSecretsProvider(secretsConfig SecretsConfig) (SecretsProvider, error) {
+	var chain chainProvider
+	for _, name := range strings.Split(secretsConfig.Providers, ",") {
+		switch strings.TrimSpace(name) {
+		case "env":
+			chain = append(chain, envProvider{})
+		case "file":
+			chain = append(chain, dirProvider{dir: secretsConfig.Dir})
+		case "encrypted":
+			provider, err := newEncryptedFileProvider(secretsConfig.File, os.Getenv("SECRETS_MASTER_KEY"))
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, provider)
+		default:
+			return nil, fmt.Errorf("SECRETS_PROVIDERS: unknown provider %q, use env, file or encrypted", name)
+		}
+	}
+	return chain, nil
+}
+
+type chainProvider []SecretsProvider
+
+func (c chainProvider) Name() string {
+	names := make([]string, len(c))
+	for i, provider := range c {
+		names[i] = provider.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+func (c chainProvider) Lookup(name string) (string, bool, error) {
+	for _, provider := range c {
+		value, found, err := provider.Lookup(name)
+		if err != nil {
+			return "", false, fmt.Errorf("%s secrets: %w", provider.Name(), err)
+		}
+		if found {
+			return value, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+type envProvider struct{}
+
+func (envProvider) Name() string { return "env" }
+
+func (envProvider) Lookup(name string) (string, bool, error) {
+	value, found := os.LookupEnv(name)
+	return value, found, nil
+}
+
+// dirProvider reads file-mounted secrets such as Docker or Kubernetes secrets,
+// one file per secret named like the variable, either as is or in lower case.
+type dirProvider struct {
+	dir string
+}
+
+func (dirProvider) Name() string { return "file" }
+
+func (d dirProvider) Lookup(name string) (string, bool, error) {
+	for _, fileName := range []string{name, strings.ToLower(name)} {
+		content, err := os.ReadFile(filepath.Join(d.dir, fileName))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", false, err
+		}
+		// Mounted files usually end with a newline that is not part of the secret
+		return strings.TrimRight(string(content), "\r\n"), true, nil
+	}
+	return "", false, nil
+}
+
+// encryptedFileProvider reads a JSON object of secrets sealed with AES-GCM by EncryptSecrets.
+type encryptedFileProvider struct {
+	secrets map[string]string
+}
+
+func newEncryptedFileProvider(file, masterKey string) (SecretsProvider, error) {
+	if file == "" || masterKey == "" {
+		return nil, errors.New("the encrypted secrets provider needs SECRETS_FILE and SECRETS_MASTER_KEY")
+	}
+	sealed, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("SECRETS_FILE: %w", err)
+	}
+	secrets, err := DecryptSecrets(sealed, masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("SECRETS_FILE: %w", err)
+	}
+	return &encryptedFileProvider{secrets: secrets}, nil
+}
+
+func (*encryptedFileProvider) Name() string { return "encrypted" }
+
+func (e *encryptedFileProvider) Lookup(name string) (string, bool, error) {
+	value, found := e.secrets[name]
+	return value, found, nil
+}
+
+// GenerateKey returns a random key of size bytes written as "base64:<key>", the form read by
+// SECRETS_MASTER_KEY (which needs 32 bytes), AES_KEY(S) and BLIND_INDEX_KEY.
+func GenerateKey(size int) (string, error) {
+	key := make([]byte, size)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return "base64:" + base64.StdEncoding.EncodeToString(key), nil
+}
+
+// EncryptSecrets seals a name to value map with the base64 master key, optionally prefixed with
+// "base64:" as printed by `secrets generate-key`. The output is
+// base64 text of the nonce followed by the ciphertext.
+func EncryptSecrets(secrets map[string]string, masterKey string) ([]byte, error) {
+	gcm, err := masterCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return []byte(base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+func DecryptSecrets(sealed []byte, masterKey string) (map[string]string, error) {
+	gcm, err := masterCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sealed)))
+	if err != nil || len(raw) < gcm.NonceSize() {
+		return nil, errors.New("secrets file is not in the expected format")
+	}
+	plaintext, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("secrets file cannot be decrypted with this master key")
+	}
+	var secrets map[string]string
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("secrets file does not hold a JSON object of strings: %w", err)
+	}
+	return secrets, nil
+}
+
+func masterCipher(masterKey string) (cipher.AEAD, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(masterKey, "base64:"))
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("SECRETS_MASTER_KEY must be a base64 encoded 32 byte key")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeyring_SingleKeyIsLegacy(t *testing.T) {
+	keyring, err := ParseKeyring("1234567812345678", "", "")
+	assert.NoError(t, err)
+	id, key := keyring.Active()
+	assert.Equal(t, LegacyKeyID, id)
+	assert.Equal(t, []byte("1234567812345678"), key)
+}
+
+func TestParseKeyring_Rotation(t *testing.T) {
+	keyring, err := ParseKeyring("ignored", "v1:aaaaaaaaaaaaaaaa, v2:base64:YmJiYmJiYmJiYmJiYmJiYg==", "v2")
+	assert.NoError(t, err)
+	id, key := keyring.Active()
+	assert.Equal(t, "v2", id)
+	assert.Equal(t, []byte("bbbbbbbbbbbbbbbb"), key)
+	old, ok := keyring.Key("v1")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("aaaaaaaaaaaaaaaa"), old)
+}
+
+func TestParseKeyring_Invalid(t *testing.T) {
+	for _, list := range []string{"no-id", "v1:a,v1:b", "bad id:key", "v1:base64:%%%"} {
+		_, err := ParseKeyring("", list, "")
+		assert.Error(t, err, list)
+	}
+	_, err := ParseKeyring("", "v1:key", "v2")
+	assert.Error(t, err)
+}
+
+func TestSecretsProvider_EnvWinsOverFile(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "db_password"), []byte("from-file\n"), 0600))
+	t.Setenv("SECRETS_DIR", dir)
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "from-file", cfg.Database.Password)
+
+	t.Setenv("DB_PASSWORD", "from-env")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", cfg.Database.Password)
+}
+
+func TestSecretsProvider_EncryptedFile(t *testing.T) {
+	masterKey, err := GenerateKey(32)
+	assert.NoError(t, err)
+	sealed, err := EncryptSecrets(map[string]string{"JWT_SECRET": "sealed-secret"}, masterKey)
+	assert.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "secrets.enc")
+	assert.NoError(t, os.WriteFile(file, sealed, 0600))
+	t.Setenv("SECRETS_PROVIDERS", "encrypted")
+	t.Setenv("SECRETS_FILE", file)
+	t.Setenv("SECRETS_MASTER_KEY", masterKey)
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "sealed-secret", cfg.Auth.JWTSecret)
+
+	otherKey, _ := GenerateKey(32)
+	t.Setenv("SECRETS_MASTER_KEY", otherKey)
+	_, err = Load()
+	assert.ErrorContains(t, err, "cannot be decrypted")
+}
+
+func TestValidate_ProductionRefusesDefaultsAndWeakKeys(t *testing.T) {
+	cfg := Defaults()
+	cfg.App.Environment = ProductionEnvironment
+	err := cfg.Validate()
+	assert.Error(t, err)
+	for _, expected := range []string{"DB_PASSWORD", "JWT_SECRET", "AES_KEY", "BLIND_INDEX_KEY", "GIN_MODE"} {
+		assert.True(t, strings.Contains(err.Error(), expected), "missing %s in %v", expected, err)
+	}
+
+	cfg.Server.GinMode = "release"
+	cfg.Database.Password = "Zq8!rT2#vLp9@wXk"
+	cfg.Auth.JWTSecrets = "2024a:base64:q7hX2bM9vR4kT1zN8cW3yP6fJ0dL5sG2aE7uH9iK4oQ=,2023b:Hs83kd92LapQmZx71NcvBt45RyUe60Wo"
+	cfg.Security.AESKeys = "k2:base64:3Jx8Lq0vZr5Tn2Wb7Yc4Hd9Mf6Ke1Ug3"
+	cfg.Security.BlindIndexKey = "base64:Vt2nQ8xK5rW1mZ7cY4hB9pL3sD6fG0jA2eU8iO5kN1w="
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, "2024a", cfg.Auth.JWTKeyring().ActiveID)
+
+	cfg.Security.AESKeys = "k2:aaaaaaaaaaaaaaaa"
+	assert.ErrorContains(t, cfg.Validate(), "AES_KEYS[k2]: too weak for production")
+}
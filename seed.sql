@@ -1 +1,3 @@
--- Add your database table setup here
\ No newline at end of file
+-- Development data loaded by `main seed`, after `main migrate up` has created the schema.
+-- The whole file runs in one transaction; keep statements idempotent so it can be re-run.
+-- Users need an Argon2id hash and salt, create them through the API or the application instead.